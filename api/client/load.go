@@ -7,6 +7,7 @@ import (
 	"golang.org/x/net/context"
 
 	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/jsonmessage"
 	flag "github.com/docker/docker/pkg/mflag"
 )
@@ -14,11 +15,12 @@ import (
 // CmdLoad loads an image from a tar archive.
 //
 // The tar archive is read from STDIN by default, or from a tar archive file.
+// An OCI image layout can also be read from a directory.
 //
 // Usage: docker load [OPTIONS]
 func (cli *DockerCli) CmdLoad(args ...string) error {
 	cmd := Cli.Subcmd("load", nil, Cli.DockerCommands["load"].Description, true)
-	infile := cmd.String([]string{"i", "-input"}, "", "Read from a tar archive file, instead of STDIN")
+	infile := cmd.String([]string{"i", "-input"}, "", "Read from a tar archive file or an OCI image layout directory, instead of STDIN")
 	quiet := cmd.Bool([]string{"q", "-quiet"}, false, "Suppress the load output")
 	cmd.Require(flag.Exact, 0)
 	cmd.ParseFlags(args, true)
@@ -31,6 +33,15 @@ func (cli *DockerCli) CmdLoad(args ...string) error {
 		}
 		defer file.Close()
 		input = file
+
+		if fi, err := file.Stat(); err == nil && fi.IsDir() {
+			layout, err := archive.Tar(*infile, archive.Uncompressed)
+			if err != nil {
+				return err
+			}
+			defer layout.Close()
+			input = layout
+		}
 	}
 	if !cli.isTerminalOut {
 		*quiet = true
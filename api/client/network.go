@@ -378,6 +378,7 @@ func networkUsage() string {
 		{"disconnect", "Disconnect container from a network"},
 		{"inspect", "Display detailed network information"},
 		{"ls", "List all networks"},
+		{"prune", "Remove all unused networks"},
 		{"rm", "Remove a network"},
 	}
 
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
+)
+
+const (
+	containerPruneWarning = "WARNING! This will remove all stopped containers."
+	volumePruneWarning    = "WARNING! This will remove all volumes not used by at least one container."
+	networkPruneWarning   = "WARNING! This will remove all networks not used by at least one container."
+	imagePruneWarning     = "WARNING! This will remove all dangling images."
+	imagePruneAllWarning  = "WARNING! This will remove all images without at least one container associated to them."
+)
+
+// CmdContainer is the parent subcommand for the container commands
+//
+// Usage: docker container <COMMAND> <OPTS>
+func (cli *DockerCli) CmdContainer(args ...string) error {
+	return parentCommandUsage("container", [][]string{
+		{"prune", "Remove all stopped containers"},
+	}, args)
+}
+
+// CmdImage is the parent subcommand for the image commands
+//
+// Usage: docker image <COMMAND> <OPTS>
+func (cli *DockerCli) CmdImage(args ...string) error {
+	return parentCommandUsage("image", [][]string{
+		{"prune", "Remove unused images"},
+	}, args)
+}
+
+// CmdContainerPrune removes all the stopped containers.
+//
+// Usage: docker container prune [OPTIONS]
+func (cli *DockerCli) CmdContainerPrune(args ...string) error {
+	cmd := Cli.Subcmd("container prune", nil, "Remove all stopped containers", true)
+	force := cmd.Bool([]string{"f", "-force"}, false, "Do not prompt for confirmation")
+	flFilter := opts.NewListOpts(nil)
+	cmd.Var(&flFilter, []string{"-filter"}, "Provide filter values (i.e. 'until=<timestamp>')")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	pruneFilters, err := parsePruneFilters(flFilter)
+	if err != nil {
+		return err
+	}
+	if !*force && !cli.confirmPrune(containerPruneWarning) {
+		return nil
+	}
+
+	report, err := cli.client.ContainersPrune(context.Background(), pruneFilters)
+	if err != nil {
+		return err
+	}
+
+	if len(report.ContainersDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Containers:")
+		for _, id := range report.ContainersDeleted {
+			fmt.Fprintln(cli.out, id)
+		}
+		fmt.Fprintln(cli.out)
+	}
+	fmt.Fprintln(cli.out, "Total reclaimed space:", units.HumanSize(float64(report.SpaceReclaimed)))
+	return nil
+}
+
+// CmdImagePrune removes the dangling images, or all the images not used by
+// a container.
+//
+// Usage: docker image prune [OPTIONS]
+func (cli *DockerCli) CmdImagePrune(args ...string) error {
+	cmd := Cli.Subcmd("image prune", nil, "Remove unused images", true)
+	all := cmd.Bool([]string{"a", "-all"}, false, "Remove all unused images, not just dangling ones")
+	force := cmd.Bool([]string{"f", "-force"}, false, "Do not prompt for confirmation")
+	flFilter := opts.NewListOpts(nil)
+	cmd.Var(&flFilter, []string{"-filter"}, "Provide filter values (i.e. 'until=<timestamp>')")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	pruneFilters, err := parsePruneFilters(flFilter)
+	if err != nil {
+		return err
+	}
+	warning := imagePruneWarning
+	if *all {
+		pruneFilters.Add("dangling", "false")
+		warning = imagePruneAllWarning
+	}
+	if !*force && !cli.confirmPrune(warning) {
+		return nil
+	}
+
+	report, err := cli.client.ImagesPrune(context.Background(), pruneFilters)
+	if err != nil {
+		return err
+	}
+
+	if len(report.ImagesDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Images:")
+		for _, d := range report.ImagesDeleted {
+			if d.Untagged != "" {
+				fmt.Fprintf(cli.out, "untagged: %s\n", d.Untagged)
+			} else {
+				fmt.Fprintf(cli.out, "deleted: %s\n", d.Deleted)
+			}
+		}
+		fmt.Fprintln(cli.out)
+	}
+	fmt.Fprintln(cli.out, "Total reclaimed space:", units.HumanSize(float64(report.SpaceReclaimed)))
+	return nil
+}
+
+// CmdVolumePrune removes all the volumes not used by a container.
+//
+// Usage: docker volume prune [OPTIONS]
+func (cli *DockerCli) CmdVolumePrune(args ...string) error {
+	cmd := Cli.Subcmd("volume prune", nil, "Remove all unused volumes", true)
+	force := cmd.Bool([]string{"f", "-force"}, false, "Do not prompt for confirmation")
+	flFilter := opts.NewListOpts(nil)
+	cmd.Var(&flFilter, []string{"-filter"}, "Provide filter values (i.e. 'label=<key>=<value>')")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	pruneFilters, err := parsePruneFilters(flFilter)
+	if err != nil {
+		return err
+	}
+	if !*force && !cli.confirmPrune(volumePruneWarning) {
+		return nil
+	}
+
+	report, err := cli.client.VolumesPrune(context.Background(), pruneFilters)
+	if err != nil {
+		return err
+	}
+
+	if len(report.VolumesDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Volumes:")
+		for _, name := range report.VolumesDeleted {
+			fmt.Fprintln(cli.out, name)
+		}
+		fmt.Fprintln(cli.out)
+	}
+	fmt.Fprintln(cli.out, "Total reclaimed space:", units.HumanSize(float64(report.SpaceReclaimed)))
+	return nil
+}
+
+// CmdNetworkPrune removes all the networks not used by a container.
+//
+// Usage: docker network prune [OPTIONS]
+func (cli *DockerCli) CmdNetworkPrune(args ...string) error {
+	cmd := Cli.Subcmd("network prune", nil, "Remove all unused networks", true)
+	force := cmd.Bool([]string{"f", "-force"}, false, "Do not prompt for confirmation")
+	flFilter := opts.NewListOpts(nil)
+	cmd.Var(&flFilter, []string{"-filter"}, "Provide filter values (i.e. 'label=<key>=<value>')")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	pruneFilters, err := parsePruneFilters(flFilter)
+	if err != nil {
+		return err
+	}
+	if !*force && !cli.confirmPrune(networkPruneWarning) {
+		return nil
+	}
+
+	report, err := cli.client.NetworksPrune(context.Background(), pruneFilters)
+	if err != nil {
+		return err
+	}
+
+	if len(report.NetworksDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Networks:")
+		for _, id := range report.NetworksDeleted {
+			fmt.Fprintln(cli.out, id)
+		}
+	}
+	return nil
+}
+
+// CmdSystemPrune removes the stopped containers, and the volumes, networks
+// and images they don't use.
+//
+// Usage: docker system prune [OPTIONS]
+func (cli *DockerCli) CmdSystemPrune(args ...string) error {
+	cmd := Cli.Subcmd("system prune", nil, "Remove unused data", true)
+	all := cmd.Bool([]string{"a", "-all"}, false, "Remove all unused images, not just dangling ones")
+	force := cmd.Bool([]string{"f", "-force"}, false, "Do not prompt for confirmation")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	warnings := []string{
+		"WARNING! This will remove:",
+		"	- all stopped containers",
+		"	- all volumes not used by at least one container",
+		"	- all networks not used by at least one container",
+	}
+	if *all {
+		warnings = append(warnings, "	- all images without at least one container associated to them")
+	} else {
+		warnings = append(warnings, "	- all dangling images")
+	}
+	if !*force && !cli.confirmPrune(strings.Join(warnings, "\n")) {
+		return nil
+	}
+
+	ctx := context.Background()
+	var spaceReclaimed uint64
+
+	containersReport, err := cli.client.ContainersPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return err
+	}
+	spaceReclaimed += containersReport.SpaceReclaimed
+	for _, id := range containersReport.ContainersDeleted {
+		fmt.Fprintln(cli.out, "Deleted container:", id)
+	}
+
+	volumesReport, err := cli.client.VolumesPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return err
+	}
+	spaceReclaimed += volumesReport.SpaceReclaimed
+	for _, name := range volumesReport.VolumesDeleted {
+		fmt.Fprintln(cli.out, "Deleted volume:", name)
+	}
+
+	networksReport, err := cli.client.NetworksPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return err
+	}
+	for _, id := range networksReport.NetworksDeleted {
+		fmt.Fprintln(cli.out, "Deleted network:", id)
+	}
+
+	imageFilters := filters.NewArgs()
+	if *all {
+		imageFilters.Add("dangling", "false")
+	}
+	imagesReport, err := cli.client.ImagesPrune(ctx, imageFilters)
+	if err != nil {
+		return err
+	}
+	spaceReclaimed += imagesReport.SpaceReclaimed
+	for _, d := range imagesReport.ImagesDeleted {
+		if d.Untagged != "" {
+			fmt.Fprintln(cli.out, "Untagged image:", d.Untagged)
+		} else {
+			fmt.Fprintln(cli.out, "Deleted image:", d.Deleted)
+		}
+	}
+
+	fmt.Fprintln(cli.out, "Total reclaimed space:", units.HumanSize(float64(spaceReclaimed)))
+	return nil
+}
+
+// parentCommandUsage prints the usage of a command that only groups
+// subcommands, such as `docker container`.
+func parentCommandUsage(name string, commands [][]string, args []string) error {
+	description := Cli.DockerCommands[name].Description + "\n\nCommands:\n"
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+	description += fmt.Sprintf("\nRun 'docker %s COMMAND --help' for more information on a command", name)
+
+	cmd := Cli.Subcmd(name, []string{"[COMMAND]"}, description, false)
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+func parsePruneFilters(flFilter opts.ListOpts) (filters.Args, error) {
+	pruneFilters := filters.NewArgs()
+	for _, f := range flFilter.GetAll() {
+		var err error
+		pruneFilters, err = filters.ParseFlag(f, pruneFilters)
+		if err != nil {
+			return pruneFilters, err
+		}
+	}
+	return pruneFilters, nil
+}
+
+// confirmPrune prints the warning and asks the user to confirm, returning
+// whether they did.
+func (cli *DockerCli) confirmPrune(warning string) bool {
+	fmt.Fprintf(cli.out, "%s\nAre you sure you want to continue? [y/N] ", warning)
+	answer := strings.ToLower(strings.TrimSpace(readInput(cli.in, cli.out)))
+	return answer == "y" || answer == "yes"
+}
@@ -2,31 +2,51 @@ package client
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"os"
 
 	"golang.org/x/net/context"
 
 	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/pkg/archive"
 	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/engine-api/types"
 )
 
 // CmdSave saves one or more images to a tar archive.
 //
 // The tar archive is written to STDOUT by default, or written to a file.
+// An OCI image layout is extracted instead when it's written to a directory.
 //
 // Usage: docker save [OPTIONS] IMAGE [IMAGE...]
 func (cli *DockerCli) CmdSave(args ...string) error {
 	cmd := Cli.Subcmd("save", []string{"IMAGE [IMAGE...]"}, Cli.DockerCommands["save"].Description+" (streamed to STDOUT by default)", true)
 	outfile := cmd.String([]string{"o", "-output"}, "", "Write to a file, instead of STDOUT")
+	format := cmd.String([]string{"-format"}, "docker", "Format of the archive, docker or oci")
 	cmd.Require(flag.Min, 1)
 
 	cmd.ParseFlags(args, true)
 
+	if *format != "docker" && *format != "oci" {
+		return fmt.Errorf("Invalid format %q, expected docker or oci", *format)
+	}
 	if *outfile == "" && cli.isTerminalOut {
 		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
 	}
 
-	responseBody, err := cli.client.ImageSave(context.Background(), cmd.Args())
+	var toDir bool
+	if *outfile != "" {
+		if fi, err := os.Stat(*outfile); err == nil && fi.IsDir() {
+			if *format != "oci" {
+				return fmt.Errorf("%s is a directory, only an OCI image layout can be saved to a directory", *outfile)
+			}
+			toDir = true
+		}
+	}
+
+	options := types.ImageSaveOptions{Format: *format}
+	responseBody, err := cli.client.ImageSave(context.Background(), cmd.Args(), options)
 	if err != nil {
 		return err
 	}
@@ -37,6 +57,9 @@ func (cli *DockerCli) CmdSave(args ...string) error {
 		return err
 	}
 
+	if toDir {
+		return archive.Untar(responseBody, *outfile, &archive.TarOptions{NoLchown: true})
+	}
 	return copyToFile(*outfile, responseBody)
 
 }
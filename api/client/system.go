@@ -0,0 +1,194 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/net/context"
+
+	Cli "github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/stringutils"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
+)
+
+// CmdSystem is the parent subcommand for all system commands
+//
+// Usage: docker system <COMMAND> <OPTS>
+func (cli *DockerCli) CmdSystem(args ...string) error {
+	description := Cli.DockerCommands["system"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"df", "Show docker disk usage"},
+		{"prune", "Remove unused data"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker system COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("system", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdSystemDf shows the disk space used by images, containers and volumes,
+// and how much of it could be reclaimed.
+//
+// Usage: docker system df [OPTIONS]
+func (cli *DockerCli) CmdSystemDf(args ...string) error {
+	cmd := Cli.Subcmd("system df", nil, "Show docker disk usage", true)
+	verbose := cmd.Bool([]string{"v", "-verbose"}, false, "Show detailed information on space usage")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	du, err := cli.client.DiskUsage(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	if *verbose {
+		printImagesDiskUsage(w, du.Images)
+		fmt.Fprintln(w)
+		printContainersDiskUsage(w, du.Containers)
+		fmt.Fprintln(w)
+		printVolumesDiskUsage(w, du.Volumes)
+	} else {
+		printDiskUsageSummary(w, du)
+	}
+	w.Flush()
+	return nil
+}
+
+func printDiskUsageSummary(w *tabwriter.Writer, du types.DiskUsage) {
+	fmt.Fprintln(w, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE")
+
+	// Layers can be shared between images, so the space used by the images
+	// that are in use is subtracted from the size of all the layers.
+	var activeImages int
+	var usedImagesSize int64
+	for _, i := range du.Images {
+		if i.Containers > 0 {
+			activeImages++
+			usedImagesSize += i.Size
+		}
+	}
+	printDiskUsageLine(w, "Images", len(du.Images), activeImages, du.LayersSize, du.LayersSize-usedImagesSize)
+
+	var activeContainers int
+	var containersSize, reclaimableContainersSize int64
+	for _, c := range du.Containers {
+		if c.SizeRw < 0 {
+			continue
+		}
+		containersSize += c.SizeRw
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" {
+			activeContainers++
+		} else {
+			reclaimableContainersSize += c.SizeRw
+		}
+	}
+	printDiskUsageLine(w, "Containers", len(du.Containers), activeContainers, containersSize, reclaimableContainersSize)
+
+	var activeVolumes int
+	var volumesSize, reclaimableVolumesSize int64
+	for _, v := range du.Volumes {
+		if v.UsageData == nil {
+			continue
+		}
+		if v.UsageData.RefCount > 0 {
+			activeVolumes++
+		}
+		if v.UsageData.Size < 0 {
+			continue
+		}
+		volumesSize += v.UsageData.Size
+		if v.UsageData.RefCount == 0 {
+			reclaimableVolumesSize += v.UsageData.Size
+		}
+	}
+	printDiskUsageLine(w, "Volumes", len(du.Volumes), activeVolumes, volumesSize, reclaimableVolumesSize)
+}
+
+func printDiskUsageLine(w *tabwriter.Writer, kind string, total, active int, size, reclaimable int64) {
+	if reclaimable < 0 {
+		reclaimable = 0
+	}
+	var percent int64
+	if size > 0 {
+		percent = reclaimable * 100 / size
+	}
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s (%d%%)\n", kind, total, active,
+		units.HumanSize(float64(size)), units.HumanSize(float64(reclaimable)), percent)
+}
+
+func printImagesDiskUsage(w *tabwriter.Writer, images []*types.Image) {
+	fmt.Fprintln(w, "Images space usage:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE\tSHARED SIZE\tUNIQUE SIZE\tCONTAINERS")
+	for _, i := range images {
+		repo, tag := "<none>", "<none>"
+		if len(i.RepoTags) > 0 && i.RepoTags[0] != "<none>:<none>" {
+			if ref, err := reference.ParseNamed(i.RepoTags[0]); err == nil {
+				repo = ref.Name()
+				if tagged, ok := ref.(reference.NamedTagged); ok {
+					tag = tagged.Tag()
+				}
+			}
+		}
+		created := units.HumanDuration(time.Now().UTC().Sub(time.Unix(i.Created, 0))) + " ago"
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", repo, tag,
+			stringid.TruncateID(i.ID), created,
+			units.HumanSize(float64(i.Size)), units.HumanSize(float64(i.SharedSize)),
+			units.HumanSize(float64(i.Size-i.SharedSize)), i.Containers)
+	}
+}
+
+func printContainersDiskUsage(w *tabwriter.Writer, containers []*types.Container) {
+	fmt.Fprintln(w, "Containers space usage:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "CONTAINER ID\tIMAGE\tCOMMAND\tLOCAL VOLUMES\tSIZE\tCREATED\tSTATUS\tNAMES")
+	for _, c := range containers {
+		var localVolumes int
+		for _, m := range c.Mounts {
+			if m.Driver == volume.DefaultDriverName {
+				localVolumes++
+			}
+		}
+		var names []string
+		for _, n := range c.Names {
+			names = append(names, strings.TrimPrefix(n, "/"))
+		}
+		created := units.HumanDuration(time.Now().UTC().Sub(time.Unix(c.Created, 0))) + " ago"
+		fmt.Fprintf(w, "%s\t%s\t%q\t%d\t%s\t%s\t%s\t%s\n",
+			stringid.TruncateID(c.ID), c.Image, stringutils.Truncate(c.Command, 20), localVolumes,
+			units.HumanSize(float64(c.SizeRw)), created, c.Status, strings.Join(names, ","))
+	}
+}
+
+func printVolumesDiskUsage(w *tabwriter.Writer, volumes []*types.Volume) {
+	fmt.Fprintln(w, "Volumes space usage:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "VOLUME NAME\tDRIVER\tLINKS\tSIZE")
+	for _, v := range volumes {
+		links, size := "N/A", "N/A"
+		if v.UsageData != nil {
+			links = fmt.Sprintf("%d", v.UsageData.RefCount)
+			if v.UsageData.Size >= 0 {
+				size = units.HumanSize(float64(v.UsageData.Size))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Name, v.Driver, links, size)
+	}
+}
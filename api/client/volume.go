@@ -24,6 +24,7 @@ func (cli *DockerCli) CmdVolume(args ...string) error {
 		{"create", "Create a volume"},
 		{"inspect", "Return low-level information on a volume"},
 		{"ls", "List volumes"},
+		{"prune", "Remove all unused volumes"},
 		{"rm", "Remove a volume"},
 	}
 
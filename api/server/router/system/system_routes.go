@@ -2,12 +2,14 @@ package system
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errors"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/events"
@@ -42,23 +44,43 @@ func (s *systemRouter) getVersion(ctx context.Context, w http.ResponseWriter, r
 	return httputils.WriteJSON(w, http.StatusOK, info)
 }
 
+func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	du, err := s.backend.SystemDiskUsage()
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, du)
+}
+
 func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
-	since, sinceNano, err := timetypes.ParseTimestamps(r.Form.Get("since"), -1)
+	since, err := eventTime(r.Form.Get("since"))
 	if err != nil {
 		return err
 	}
-	until, untilNano, err := timetypes.ParseTimestamps(r.Form.Get("until"), -1)
+	until, err := eventTime(r.Form.Get("until"))
 	if err != nil {
 		return err
 	}
 
-	var timeout <-chan time.Time
-	if until > 0 || untilNano > 0 {
-		dur := time.Unix(until, untilNano).Sub(time.Now())
-		timeout = time.NewTimer(dur).C
+	var (
+		timeout        <-chan time.Time
+		onlyPastEvents bool
+	)
+	if !until.IsZero() {
+		if until.Before(since) {
+			return errors.NewBadRequestError(fmt.Errorf("`since` time (%s) cannot be after `until` time (%s)", r.Form.Get("since"), r.Form.Get("until")))
+		}
+
+		now := time.Now()
+		onlyPastEvents = until.Before(now)
+		if !onlyPastEvents {
+			dur := until.Sub(now)
+			timeout = time.NewTimer(dur).C
+		}
 	}
 
 	ef, err := filters.FromParam(r.Form.Get("filters"))
@@ -73,7 +95,7 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 
 	enc := json.NewEncoder(output)
 
-	buffered, l := s.backend.SubscribeToEvents(since, sinceNano, ef)
+	buffered, l := s.backend.SubscribeToEvents(since, until, ef)
 	defer s.backend.UnsubscribeFromEvents(l)
 
 	for _, ev := range buffered {
@@ -82,6 +104,10 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 		}
 	}
 
+	if onlyPastEvents {
+		return nil
+	}
+
 	for {
 		select {
 		case ev := <-l:
@@ -118,3 +144,16 @@ func (s *systemRouter) postAuth(ctx context.Context, w http.ResponseWriter, r *h
 		IdentityToken: token,
 	})
 }
+
+// eventTime parses a `since` or `until` parameter of the events endpoint.
+// It returns the zero time if the parameter isn't set.
+func eventTime(formTime string) (time.Time, error) {
+	t, tNano, err := timetypes.ParseTimestamps(formTime, -1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t == -1 {
+		return time.Time{}, nil
+	}
+	return time.Unix(t, tNano), nil
+}
@@ -138,6 +138,14 @@ type Backend interface {
 	//ContainerCopy(name string, res string) (io.ReadCloser, error)
 	// TODO: use copyBackend api
 	CopyOnBuild(containerID string, destPath string, src FileInfo, decompress bool) error
+
+	// MountImage mounts the root filesystem of the image referenced by `name`
+	// and returns its path, together with a function to release the mount.
+	MountImage(name string) (string, func() error, error)
+
+	// SquashImage creates a new image whose changes since `parent` are
+	// merged into a single layer.
+	SquashImage(id, parent string) (string, error)
 }
 
 // Image represents a Docker image used by the builder.
@@ -63,13 +63,15 @@ type Builder struct {
 	runConfig        *container.Config // runconfig for cmd, run, entrypoint etc.
 	flags            *BFlags
 	tmpContainers    map[string]struct{}
-	image            string // imageID
+	image            string        // imageID
+	from             builder.Image // image the current build stage is based on
 	noBaseImage      bool
 	maintainer       string
 	cmdSet           bool
 	disableCommit    bool
 	cacheBusted      bool
 	allowedBuildArgs map[string]bool // list of build-time args that are allowed for expansion/substitution and passing to commands in 'run'.
+	imageContexts    imageContexts   // stages of a multi-stage build and images mounted for COPY --from
 
 	// TODO: remove once docker.Commit can receive a tag
 	id string
@@ -212,6 +214,8 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		return "", err
 	}
 
+	defer b.imageContexts.unmount()
+
 	var shortImgID string
 	for i, n := range b.dockerfile.Children {
 		// we only want to add labels to the last layer
@@ -266,6 +270,18 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		return "", fmt.Errorf("No image was generated. Is your Dockerfile empty?")
 	}
 
+	if b.options.Squash {
+		var fromID string
+		if b.from != nil {
+			fromID = b.from.ImageID()
+		}
+		b.image, err = b.docker.SquashImage(b.image, fromID)
+		if err != nil {
+			return "", fmt.Errorf("error squashing image: %v", err)
+		}
+		shortImgID = stringid.TruncateID(b.image)
+	}
+
 	imageID := image.ID(b.image)
 	for _, rt := range repoAndTags {
 		if err := b.docker.TagImageWithReference(imageID, rt); err != nil {
@@ -0,0 +1,113 @@
+package dockerfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/builder"
+)
+
+// buildStage is one `FROM` section of a Dockerfile. Every stage but the
+// last one only exists so that its results can be copied into later stages
+// with `COPY --from`.
+type buildStage struct {
+	name  string // lower-cased name given with `FROM image AS name`
+	image string // ID of the image the stage ended with
+}
+
+// imageMount is the mounted root filesystem of an image used as the source
+// of a `COPY --from`.
+type imageMount struct {
+	ctx     builder.Context
+	release func() error
+}
+
+// imageContexts keeps track of the stages of a multi-stage build and of the
+// images that have been mounted to copy files out of them.
+type imageContexts struct {
+	stages  []*buildStage
+	current *buildStage
+	mounts  map[string]*imageMount
+}
+
+// newStage finishes the current stage, if any, and starts a new one.
+// image is the ID the current stage ended with.
+func (ic *imageContexts) newStage(name, image string) error {
+	name = strings.ToLower(name)
+	if name != "" {
+		if _, err := strconv.Atoi(name); err == nil {
+			return fmt.Errorf("invalid name for build stage: %q, name can't be a number", name)
+		}
+		for _, s := range ic.stages {
+			if s.name == name {
+				return fmt.Errorf("duplicate name for build stage: %q", name)
+			}
+		}
+		if ic.current != nil && ic.current.name == name {
+			return fmt.Errorf("duplicate name for build stage: %q", name)
+		}
+	}
+
+	if ic.current != nil {
+		ic.current.image = image
+		ic.stages = append(ic.stages, ic.current)
+	}
+	ic.current = &buildStage{name: name}
+	return nil
+}
+
+// lookup returns the image a finished stage ended with. A stage can be
+// referred to by its name or by its index, starting at 0. The second return
+// value is false if nameOrIndex doesn't refer to a stage.
+func (ic *imageContexts) lookup(nameOrIndex string) (string, bool, error) {
+	name := strings.ToLower(nameOrIndex)
+	if ic.current != nil && ic.current.name != "" && ic.current.name == name {
+		return "", false, fmt.Errorf("%s refers to the current build stage", nameOrIndex)
+	}
+	for _, s := range ic.stages {
+		if s.name != "" && s.name == name {
+			return s.image, true, nil
+		}
+	}
+	if i, err := strconv.Atoi(name); err == nil {
+		if i < 0 || i >= len(ic.stages) {
+			return "", false, fmt.Errorf("invalid build stage index %d", i)
+		}
+		return ic.stages[i].image, true, nil
+	}
+	return "", false, nil
+}
+
+// mount returns a Context for the root filesystem of image, mounting it
+// through the backend the first time it is used.
+func (ic *imageContexts) mount(backend builder.Backend, image string) (builder.Context, error) {
+	if m, ok := ic.mounts[image]; ok {
+		return m.ctx, nil
+	}
+	root, release, err := backend.MountImage(image)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := builder.NewLazyContext(root)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if ic.mounts == nil {
+		ic.mounts = make(map[string]*imageMount)
+	}
+	ic.mounts[image] = &imageMount{ctx: ctx, release: release}
+	return ctx, nil
+}
+
+// unmount releases all the images mounted during the build.
+func (ic *imageContexts) unmount() {
+	for image, m := range ic.mounts {
+		if err := m.release(); err != nil {
+			logrus.Warnf("[BUILDER] failed to unmount image %s: %v", image, err)
+		}
+		delete(ic.mounts, image)
+	}
+}
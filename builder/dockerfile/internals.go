@@ -110,8 +110,52 @@ type copyInfo struct {
 	decompress bool
 }
 
-func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
-	if b.context == nil {
+// resetStage clears the state carried over from the previous build stage
+// before a new FROM is processed.
+func (b *Builder) resetStage() {
+	b.runConfig = new(container.Config)
+	b.image = ""
+	b.noBaseImage = false
+	b.maintainer = ""
+	b.cmdSet = false
+	b.cacheBusted = false
+}
+
+// sourceFromImage returns the Context to copy files from for `COPY --from`.
+// nameOrIndex is either the name or index of an earlier build stage, or an
+// image reference, which is pulled if it doesn't exist locally.
+func (b *Builder) sourceFromImage(nameOrIndex string) (builder.Context, error) {
+	if nameOrIndex == "" {
+		return nil, fmt.Errorf("--from requires a build stage or an image")
+	}
+
+	imageID, isStage, err := b.imageContexts.lookup(nameOrIndex)
+	if err != nil {
+		return nil, err
+	}
+	if isStage && imageID == "" {
+		return nil, fmt.Errorf("build stage %s has no image to copy from", nameOrIndex)
+	}
+	if !isStage {
+		var img builder.Image
+		if !b.options.PullParent {
+			img, _ = b.docker.GetImageOnBuild(nameOrIndex)
+		}
+		if img == nil {
+			if img, err = b.docker.PullOnBuild(b.clientCtx, nameOrIndex, b.options.AuthConfigs, b.Output); err != nil {
+				return nil, err
+			}
+		}
+		imageID = img.ImageID()
+	}
+
+	return b.imageContexts.mount(b.docker, imageID)
+}
+
+// runContextCommand copies the files named by all but the last of args
+// from src to the destination given by the last one, and commits the result.
+func (b *Builder) runContextCommand(src builder.Context, args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
+	if src == nil {
 		return fmt.Errorf("No context given. Impossible to use %s", cmdName)
 	}
 
@@ -147,7 +191,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 			continue
 		}
 		// not a URL
-		subInfos, err := b.calcCopyInfo(cmdName, orig, allowLocalDecompression, true)
+		subInfos, err := calcCopyInfo(src, cmdName, orig, allowLocalDecompression, true)
 		if err != nil {
 			return err
 		}
@@ -321,7 +365,7 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	return &builder.HashedFileInfo{FileInfo: builder.PathFileInfo{FileInfo: tmpFileSt, FilePath: tmpFileName}, FileHash: hash}, nil
 }
 
-func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
+func calcCopyInfo(src builder.Context, cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
 
 	// Work in daemon-specific OS filepath semantics
 	origPath = filepath.FromSlash(origPath)
@@ -334,7 +378,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	// Deal with wildcards
 	if allowWildcards && containsWildcards(origPath) {
 		var copyInfos []copyInfo
-		if err := b.context.Walk("", func(path string, info builder.FileInfo, err error) error {
+		if err := src.Walk("", func(path string, info builder.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -348,7 +392,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 			// Note we set allowWildcards to false in case the name has
 			// a * in it
-			subInfos, err := b.calcCopyInfo(cmdName, path, allowLocalDecompression, false)
+			subInfos, err := calcCopyInfo(src, cmdName, path, allowLocalDecompression, false)
 			if err != nil {
 				return err
 			}
@@ -362,7 +406,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 	// Must be a dir or a file
 
-	statPath, fi, err := b.context.Stat(origPath)
+	statPath, fi, err := src.Stat(origPath)
 	if err != nil {
 		return nil, err
 	}
@@ -381,7 +425,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	}
 	// Must be a dir
 	var subfiles []string
-	err = b.context.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
+	err = src.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -414,6 +458,7 @@ func containsWildcards(name string) bool {
 }
 
 func (b *Builder) processImageFrom(img builder.Image) error {
+	b.from = img
 	if img != nil {
 		b.image = img.ImageID()
 
@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/symlink"
+)
+
+// lazyContext is a Context backed by an existing directory, such as the
+// mounted root filesystem of an image. Unlike tarSumContext, file hashes are
+// only computed when a file is first looked at.
+type lazyContext struct {
+	root string
+	sums map[string]string
+}
+
+// NewLazyContext returns a Context for the directory tree rooted at root.
+// Closing the Context does not remove the directory.
+func NewLazyContext(root string) (Context, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+	return &lazyContext{root: root, sums: make(map[string]string)}, nil
+}
+
+func (c *lazyContext) Close() error {
+	return nil
+}
+
+func (c *lazyContext) Open(path string) (io.ReadCloser, error) {
+	cleanpath, fullpath, err := c.normalize(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := os.Open(fullpath)
+	if err != nil {
+		return nil, convertPathError(err, cleanpath)
+	}
+	return r, nil
+}
+
+func (c *lazyContext) Stat(path string) (string, FileInfo, error) {
+	cleanpath, fullpath, err := c.normalize(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	st, err := os.Lstat(fullpath)
+	if err != nil {
+		return "", nil, convertPathError(err, cleanpath)
+	}
+
+	rel, err := filepath.Rel(c.root, fullpath)
+	if err != nil {
+		return "", nil, convertPathError(err, cleanpath)
+	}
+
+	sum, err := c.hash(rel, st)
+	if err != nil {
+		return "", nil, err
+	}
+	fi := &HashedFileInfo{PathFileInfo{st, fullpath, filepath.Base(cleanpath)}, sum}
+	return rel, fi, nil
+}
+
+func (c *lazyContext) Walk(root string, walkFn WalkFunc) error {
+	root = filepath.Join(c.root, filepath.Join(string(filepath.Separator), root))
+	return filepath.Walk(root, func(fullpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(c.root, fullpath)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		sum, err := c.hash(rel, info)
+		if err != nil {
+			return err
+		}
+		fi := &HashedFileInfo{PathFileInfo{FileInfo: info, FilePath: fullpath}, sum}
+		return walkFn(rel, fi, nil)
+	})
+}
+
+func (c *lazyContext) normalize(path string) (cleanpath, fullpath string, err error) {
+	cleanpath = filepath.Clean(string(os.PathSeparator) + path)[1:]
+	fullpath, err = symlink.FollowSymlinkInScope(filepath.Join(c.root, path), c.root)
+	if err != nil {
+		return "", "", fmt.Errorf("Forbidden path outside the source filesystem: %s (%s)", path, fullpath)
+	}
+	if _, err = os.Lstat(fullpath); err != nil {
+		return "", "", convertPathError(err, path)
+	}
+	return
+}
+
+// hash returns the checksum of the file at rel, computing it on first use.
+// The checksum covers the path, mode and contents (or link target), which
+// is what the builder cache needs to know whether a copy has changed.
+func (c *lazyContext) hash(rel string, fi os.FileInfo) (string, error) {
+	if sum, ok := c.sums[rel]; ok {
+		return sum, nil
+	}
+
+	fullpath := filepath.Join(c.root, rel)
+	h := sha256.New()
+	fmt.Fprintf(h, "name:%s,mode:%v,size:%d", filepath.ToSlash(rel), fi.Mode(), fi.Size())
+	switch {
+	case fi.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(fullpath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, ",link:%s", target)
+	case fi.Mode().IsRegular() && fi.Size() > 0:
+		f, err := os.Open(fullpath)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	c.sums[rel] = sum
+	return sum, nil
+}
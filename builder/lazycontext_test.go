@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLazyContextStatAndWalk(t *testing.T) {
+	root, err := ioutil.TempDir("", "builder-lazycontext-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	if err := os.MkdirAll(filepath.Join(root, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "dir", "file"), []byte("contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := NewLazyContext(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	rel, fi, err := ctx.Stat("/dir/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel != filepath.Join("dir", "file") {
+		t.Fatalf("Expected relative path dir/file, got %s", rel)
+	}
+	sum := fi.(Hashed).Hash()
+	if sum == "" {
+		t.Fatal("Expected a non-empty hash")
+	}
+
+	var walked []string
+	err = ctx.Walk("", func(path string, fi FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, path)
+		if path == rel && fi.(Hashed).Hash() != sum {
+			t.Fatalf("Walk and Stat returned different hashes for %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(walked) != 2 {
+		t.Fatalf("Expected to walk 2 entries, got %v", walked)
+	}
+
+	// Changing the contents must change the hash seen by a new context
+	if err := ioutil.WriteFile(filepath.Join(root, "dir", "file"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx2, err := NewLazyContext(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fi, err = ctx2.Stat("dir/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.(Hashed).Hash() == sum {
+		t.Fatal("Expected the hash to change with the file contents")
+	}
+
+	if _, _, err := ctx.Stat("../../etc/passwd"); err == nil {
+		t.Fatal("Expected an error for a path outside of the context")
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/docker/daemon/logger/local"
+	"github.com/docker/docker/daemon/logger/loggerutils/cache"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -33,6 +35,7 @@ import (
 	containertypes "github.com/docker/engine-api/types/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/docker/libnetwork"
 	"github.com/docker/libnetwork/netlabel"
 	"github.com/docker/libnetwork/options"
@@ -70,6 +73,7 @@ type CommonContainer struct {
 	MountLabel             string
 	ProcessLabel           string
 	RestartCount           int
+	LastRestartReason      string // why the restart policy last restarted the container
 	HasBeenStartedBefore   bool
 	HasBeenManuallyStopped bool // used for unless-stopped restart policy
 	MountPoints            map[string]*volume.MountPoint
@@ -296,7 +300,63 @@ func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Lo
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get logging factory: %v", err)
 	}
-	ctx := logger.Context{
+	ctx := container.logContext(cfg)
+
+	// Set logging file for "json-logger" and "local"
+	switch cfg.Type {
+	case jsonfilelog.Name:
+		ctx.LogPath, err = container.GetRootResourcePath(fmt.Sprintf("%s-json.log", container.ID))
+		if err != nil {
+			return nil, err
+		}
+	case local.Name:
+		ctx.LogPath, err = container.GetRootResourcePath("container-local.log")
+		if err != nil {
+			return nil, err
+		}
+	}
+	l, err := c(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a local copy of the logs of the drivers that can't read them back
+	cachePath, err := container.GetRootResourcePath("container-cached.log")
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	cached, err := cache.WithLocalCache(l, ctx, cachePath)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = cached
+
+	if cfg.Config[logger.ModeOpt] == logger.ModeNonBlocking {
+		bufferSize := int64(-1)
+		if s, ok := cfg.Config[logger.MaxBufferSizeOpt]; ok {
+			bufferSize, err = units.RAMInBytes(s)
+			if err != nil {
+				l.Close()
+				return nil, err
+			}
+		}
+		l = logger.NewRingLogger(l, bufferSize)
+	}
+	return l, nil
+}
+
+// LogAttributes returns the attributes added to the log messages of the
+// container, selected among its labels, including the ones of its image,
+// and its environment variables with the labels and env logging options.
+func (container *Container) LogAttributes(cfg containertypes.LogConfig) logger.LogAttributes {
+	ctx := container.logContext(cfg)
+	return ctx.LogAttributes()
+}
+
+func (container *Container) logContext(cfg containertypes.LogConfig) logger.Context {
+	return logger.Context{
 		Config:              cfg.Config,
 		ContainerID:         container.ID,
 		ContainerName:       container.Name,
@@ -308,15 +368,6 @@ func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Lo
 		ContainerEnv:        container.Config.Env,
 		ContainerLabels:     container.Config.Labels,
 	}
-
-	// Set logging file for "json-logger"
-	if cfg.Type == jsonfilelog.Name {
-		ctx.LogPath, err = container.GetRootResourcePath(fmt.Sprintf("%s-json.log", container.ID))
-		if err != nil {
-			return nil, err
-		}
-	}
-	return c(ctx)
 }
 
 // GetProcessLabel returns the process label for the container.
@@ -519,7 +570,7 @@ func copyEscapable(dst io.Writer, src io.ReadCloser, keys []byte) (written int64
 // ShouldRestart decides whether the daemon should restart the container or not.
 // This is based on the container's restart policy.
 func (container *Container) ShouldRestart() bool {
-	shouldRestart, _, _ := container.restartManager.ShouldRestart(uint32(container.ExitCode), container.HasBeenManuallyStopped)
+	shouldRestart, _, _ := container.restartManager.ShouldRestart(uint32(container.ExitCode), container.HasBeenManuallyStopped, container.FinishedAt.Sub(container.StartedAt))
 	return shouldRestart
 }
 
@@ -908,6 +959,7 @@ func (container *Container) FullHostname() string {
 func (container *Container) RestartManager(reset bool) restartmanager.RestartManager {
 	if reset {
 		container.RestartCount = 0
+		container.LastRestartReason = ""
 		container.restartManager = nil
 	}
 	if container.restartManager == nil {
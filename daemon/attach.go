@@ -79,6 +79,9 @@ func (daemon *Daemon) containerAttach(container *container.Container, stdin io.R
 		if err != nil {
 			return err
 		}
+		if logDriver != container.LogDriver {
+			defer logDriver.Close()
+		}
 		cLog, ok := logDriver.(logger.LogReader)
 		if !ok {
 			return logger.ErrReadLogsNotSupported
@@ -6,20 +6,31 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/discovery"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/registry"
+	"github.com/docker/go-units"
 	"github.com/imdario/mergo"
 )
 
 const (
 	defaultNetworkMtu    = 1500
 	disableNetworkBridge = "none"
+
+	// defaultMaxConcurrentDownloads is the default maximum number of
+	// downloads that may take place at a time for each pull.
+	defaultMaxConcurrentDownloads = 3
+	// defaultMaxConcurrentUploads is the default maximum number of uploads
+	// that may take place at a time for each push.
+	defaultMaxConcurrentUploads = 5
 )
 
 // flatOptions contains configuration keys
@@ -27,8 +38,17 @@ const (
 // Use this to differentiate these options
 // with others like the ones in CommonTLSOptions.
 var flatOptions = map[string]bool{
-	"cluster-store-opts": true,
-	"log-opts":           true,
+	"cluster-store-opts":                true,
+	"log-opts":                          true,
+	"registry-max-concurrent-downloads": true,
+	"registry-mirrors-by-host":          true,
+}
+
+// fileOnlyOptions contains the configuration keys
+// that can only be set in the configuration file,
+// as they have no equivalent flag.
+var fileOnlyOptions = map[string]bool{
+	"registry-mirrors-by-host": true,
 }
 
 // LogConfig represents the default log configuration.
@@ -67,6 +87,7 @@ type CommonConfig struct {
 	DNS                  []string            `json:"dns,omitempty"`
 	DNSOptions           []string            `json:"dns-opts,omitempty"`
 	DNSSearch            []string            `json:"dns-search,omitempty"`
+	EventsLogMaxSize     string              `json:"events-log-max-size,omitempty"`
 	ExecOptions          []string            `json:"exec-opts,omitempty"`
 	GraphDriver          string              `json:"storage-driver,omitempty"`
 	GraphOptions         []string            `json:"storage-opts,omitempty"`
@@ -92,6 +113,19 @@ type CommonConfig struct {
 	// reachable by other hosts.
 	ClusterAdvertise string `json:"cluster-advertise,omitempty"`
 
+	// MaxConcurrentDownloads is the maximum number of downloads that
+	// may take place at a time for each pull.
+	MaxConcurrentDownloads *int `json:"max-concurrent-downloads,omitempty"`
+
+	// MaxConcurrentUploads is the maximum number of uploads that
+	// may take place at a time for each push.
+	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
+
+	// RegistryMaxConcurrentDownloads is the maximum number of downloads
+	// that may take place at a time from each of the given registries,
+	// by registry. These downloads don't count in MaxConcurrentDownloads.
+	RegistryMaxConcurrentDownloads map[string]string `json:"registry-max-concurrent-downloads,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -135,6 +169,14 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.ClusterAdvertise, []string{"-cluster-advertise"}, "", usageFn("Address or interface name to advertise"))
 	cmd.StringVar(&config.ClusterStore, []string{"-cluster-store"}, "", usageFn("Set the cluster store"))
 	cmd.Var(opts.NewNamedMapOpts("cluster-store-opts", config.ClusterOpts, nil), []string{"-cluster-store-opt"}, usageFn("Set cluster store options"))
+	cmd.StringVar(&config.EventsLogMaxSize, []string{"-events-log-max-size"}, "", usageFn("Keep a journal of the events on disk, up to the given size"))
+
+	var maxConcurrentDownloads, maxConcurrentUploads int
+	config.MaxConcurrentDownloads = &maxConcurrentDownloads
+	config.MaxConcurrentUploads = &maxConcurrentUploads
+	cmd.IntVar(&maxConcurrentDownloads, []string{"-max-concurrent-downloads"}, defaultMaxConcurrentDownloads, usageFn("Set the max concurrent downloads for each pull"))
+	cmd.IntVar(&maxConcurrentUploads, []string{"-max-concurrent-uploads"}, defaultMaxConcurrentUploads, usageFn("Set the max concurrent uploads for each push"))
+	cmd.Var(opts.NewNamedMapOpts("registry-max-concurrent-downloads", config.RegistryMaxConcurrentDownloads, nil), []string{"-registry-max-concurrent-downloads"}, usageFn("Set the max concurrent downloads from a registry"))
 }
 
 // IsValueSet returns true if a configuration value
@@ -292,7 +334,7 @@ func findConfigurationConflicts(config map[string]interface{}, flags *flag.FlagS
 	unknownKeys := make(map[string]interface{})
 	for key, value := range config {
 		flagName := "-" + key
-		if flag := flags.Lookup(flagName); flag == nil {
+		if flag := flags.Lookup(flagName); flag == nil && !fileOnlyOptions[key] {
 			unknownKeys[key] = value
 		}
 	}
@@ -375,5 +417,74 @@ func validateConfiguration(config *Config) error {
 		}
 	}
 
+	// validate registry mirrors and insecure registries
+	for _, mirror := range config.Mirrors {
+		if _, err := registry.ValidateMirror(mirror); err != nil {
+			return err
+		}
+	}
+	if err := registry.ValidateMirrorsByHost(config.MirrorsByHost); err != nil {
+		return err
+	}
+	for _, r := range config.InsecureRegistries {
+		if _, err := registry.ValidateIndexName(r); err != nil {
+			return err
+		}
+	}
+
+	// validate MaxConcurrentDownloads and MaxConcurrentUploads
+	if config.MaxConcurrentDownloads != nil && *config.MaxConcurrentDownloads <= 0 {
+		return fmt.Errorf("invalid max concurrent downloads: %d", *config.MaxConcurrentDownloads)
+	}
+	if config.MaxConcurrentUploads != nil && *config.MaxConcurrentUploads <= 0 {
+		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
+	}
+	if _, err := registryConcurrency(config.RegistryMaxConcurrentDownloads); err != nil {
+		return err
+	}
+
+	// validate the log driver and its options
+	if config.LogConfig.Type != "" && config.LogConfig.Type != "none" {
+		if _, err := logger.GetLogDriver(config.LogConfig.Type); err != nil {
+			return fmt.Errorf("error finding the logging driver: %v", err)
+		}
+	}
+	if err := logger.ValidateLogOpts(config.LogConfig.Type, config.LogConfig.Config); err != nil {
+		return err
+	}
+
+	// validate EventsLogMaxSize
+	if config.EventsLogMaxSize != "" {
+		if _, err := units.RAMInBytes(config.EventsLogMaxSize); err != nil {
+			return fmt.Errorf("invalid events-log-max-size: %v", err)
+		}
+	}
+
 	return nil
 }
+
+// registryConcurrency returns the max concurrent downloads by registry host.
+// The registries are given by host, and port if any, or by URL like the
+// registry mirrors.
+func registryConcurrency(limits map[string]string) (map[string]int, error) {
+	concurrency := make(map[string]int, len(limits))
+	for r, limit := range limits {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid max concurrent downloads for registry %s: %s", r, limit)
+		}
+		host := r
+		if strings.Contains(r, "://") {
+			u, err := url.Parse(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid registry %s: %v", r, err)
+			}
+			host = u.Host
+		}
+		if host == "" || strings.Contains(host, "/") {
+			return nil, fmt.Errorf("invalid registry %s for the max concurrent downloads", r)
+		}
+		concurrency[host] = n
+	}
+	return concurrency, nil
+}
@@ -3,6 +3,7 @@ package daemon
 import (
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -185,6 +186,15 @@ func TestFindConfigurationConflictsWithUnknownKeys(t *testing.T) {
 	}
 }
 
+func TestFindConfigurationConflictsWithFileOnlyKeys(t *testing.T) {
+	config := map[string]interface{}{"registry-mirrors-by-host": map[string]interface{}{}}
+	flags := mflag.NewFlagSet("test", mflag.ContinueOnError)
+
+	if err := findConfigurationConflicts(config, flags); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestFindConfigurationConflictsWithMergedValues(t *testing.T) {
 	var hosts []string
 	config := map[string]interface{}{"hosts": "tcp://127.0.0.1:2345"}
@@ -276,3 +286,28 @@ func TestValidateConfiguration(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestRegistryConcurrency(t *testing.T) {
+	for _, limits := range []map[string]string{
+		{"mirror.example.com": "0"},
+		{"mirror.example.com": "many"},
+		{"": "2"},
+		{"mirror.example.com/v2": "2"},
+	} {
+		if _, err := registryConcurrency(limits); err == nil {
+			t.Fatalf("expected an error with %v", limits)
+		}
+	}
+
+	concurrency, err := registryConcurrency(map[string]string{
+		"mirror.example.com:5000":    "2",
+		"https://docker.example.com": "1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"mirror.example.com:5000": 2, "docker.example.com": 1}
+	if !reflect.DeepEqual(concurrency, expected) {
+		t.Fatalf("expected %v, got %v", expected, concurrency)
+	}
+}
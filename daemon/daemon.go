@@ -6,6 +6,7 @@
 package daemon
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,7 +14,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -67,20 +70,16 @@ import (
 	"github.com/docker/docker/volume/local"
 	"github.com/docker/docker/volume/store"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/docker/libnetwork"
 	nwconfig "github.com/docker/libnetwork/config"
 	"github.com/docker/libtrust"
 	"golang.org/x/net/context"
 )
 
-const (
-	// maxDownloadConcurrency is the maximum number of downloads that
-	// may take place at a time for each pull.
-	maxDownloadConcurrency = 3
-	// maxUploadConcurrency is the maximum number of uploads that
-	// may take place at a time for each push.
-	maxUploadConcurrency = 5
-)
+// partialBlobsMaxAge is how long the partially downloaded blobs are kept to
+// resume their download, since the last attempt.
+const partialBlobsMaxAge = 24 * time.Hour
 
 var (
 	validContainerNameChars   = utils.RestrictedNameChars
@@ -108,13 +107,16 @@ type Daemon struct {
 	downloadManager           *xfer.LayerDownloadManager
 	uploadManager             *xfer.LayerUploadManager
 	distributionMetadataStore dmetadata.Store
+	partialBlobStore          *xfer.PartialBlobStore
 	trustKey                  libtrust.PrivateKey
 	idIndex                   *truncindex.TruncIndex
 	configStore               *Config
 	statsCollector            *statsCollector
 	defaultLogConfig          containertypes.LogConfig
+	defaultLogConfigLock      sync.Mutex // protects defaultLogConfig, which can be reloaded
 	RegistryService           *registry.Service
 	EventsService             *events.Events
+	eventsJournal             *events.Journal
 	netController             libnetwork.NetworkController
 	volumes                   *store.VolumeStore
 	discoveryWatcher          discoveryReloader
@@ -548,9 +550,9 @@ func (daemon *Daemon) GetByName(name string) (*container.Container, error) {
 }
 
 // SubscribeToEvents returns the currently record of events, a channel to stream new events from, and a function to cancel the stream of events.
-func (daemon *Daemon) SubscribeToEvents(since, sinceNano int64, filter filters.Args) ([]eventtypes.Message, chan interface{}) {
+func (daemon *Daemon) SubscribeToEvents(since, until time.Time, filter filters.Args) ([]eventtypes.Message, chan interface{}) {
 	ef := events.NewFilter(filter)
-	return daemon.EventsService.SubscribeTopic(since, sinceNano, ef)
+	return daemon.EventsService.SubscribeTopic(since, until, ef)
 }
 
 // UnsubscribeFromEvents stops the event subscription for a client by closing the
@@ -717,7 +719,21 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 		return nil, err
 	}
 
+	maxDownloadConcurrency, maxUploadConcurrency := defaultMaxConcurrentDownloads, defaultMaxConcurrentUploads
+	if config.MaxConcurrentDownloads != nil {
+		maxDownloadConcurrency = *config.MaxConcurrentDownloads
+	}
+	if config.MaxConcurrentUploads != nil {
+		maxUploadConcurrency = *config.MaxConcurrentUploads
+	}
+	logrus.Debugf("Max Concurrent Downloads: %d", maxDownloadConcurrency)
 	d.downloadManager = xfer.NewLayerDownloadManager(d.layerStore, maxDownloadConcurrency)
+	registryLimits, err := registryConcurrency(config.RegistryMaxConcurrentDownloads)
+	if err != nil {
+		return nil, err
+	}
+	d.downloadManager.SetRegistryConcurrency(registryLimits)
+	logrus.Debugf("Max Concurrent Uploads: %d", maxUploadConcurrency)
 	d.uploadManager = xfer.NewLayerUploadManager(maxUploadConcurrency)
 
 	ifs, err := image.NewFSStoreBackend(filepath.Join(imageRoot, "imagedb"))
@@ -752,7 +768,24 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 		return nil, err
 	}
 
+	d.partialBlobStore, err = xfer.NewPartialBlobStore(filepath.Join(imageRoot, "partial"), partialBlobsMaxAge)
+	if err != nil {
+		return nil, err
+	}
+
 	eventsService := events.New()
+	if config.EventsLogMaxSize != "" {
+		maxSize, err := units.RAMInBytes(config.EventsLogMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid events-log-max-size: %v", err)
+		}
+		journal, err := events.NewJournal(filepath.Join(config.Root, "events", "events.log"), maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't open the events journal: %v", err)
+		}
+		eventsService.SetJournal(journal)
+		d.eventsJournal = journal
+	}
 
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
 	if err != nil {
@@ -822,6 +855,8 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 		return nil, err
 	}
 
+	d.LogDaemonEventWithAttributes("start", map[string]string{})
+
 	return d, nil
 }
 
@@ -867,6 +902,8 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 // Shutdown stops the daemon.
 func (daemon *Daemon) Shutdown() error {
 	daemon.shutdown = true
+	daemon.LogDaemonEventWithAttributes("shutdown", map[string]string{})
+
 	if daemon.containers != nil {
 		logrus.Debug("starting clean shutdown of all containers...")
 		daemon.containers.ApplyAll(func(c *container.Container) {
@@ -900,6 +937,12 @@ func (daemon *Daemon) Shutdown() error {
 		return err
 	}
 
+	if daemon.eventsJournal != nil {
+		if err := daemon.eventsJournal.Close(); err != nil {
+			logrus.Errorf("Error closing the events journal: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -984,11 +1027,18 @@ func isBrokenPipe(e error) bool {
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
-// outStream is the writer which the images are written to.
-func (daemon *Daemon) ExportImage(names []string, outStream io.Writer) error {
+// the same tag are exported. names is the set of tags to export, format
+// is the layout of the tar, "docker" or "oci", and outStream is the writer
+// which the images are written to.
+func (daemon *Daemon) ExportImage(names []string, format string, outStream io.Writer) error {
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore)
-	return imageExporter.Save(names, outStream)
+	switch format {
+	case "", "docker":
+		return imageExporter.Save(names, outStream)
+	case "oci":
+		return imageExporter.SaveOCI(names, outStream)
+	}
+	return fmt.Errorf("invalid format %q, expected docker or oci", format)
 }
 
 // LookupImage looks up an image by name and returns it as an ImageInspect
@@ -1064,7 +1114,7 @@ func (daemon *Daemon) LookupImage(name string) (*types.ImageInspect, error) {
 
 // LoadImage uploads a set of images into the repository. This is the
 // complement of ImageExport.  The input stream is an uncompressed tar
-// ball containing images and metadata.
+// ball containing images and metadata, or an OCI image layout.
 func (daemon *Daemon) LoadImage(inTar io.ReadCloser, outStream io.Writer, quiet bool) error {
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore)
 	return imageExporter.Load(inTar, outStream, quiet)
@@ -1196,6 +1246,41 @@ func (daemon *Daemon) GetImageOnBuild(name string) (builder.Image, error) {
 	return img, nil
 }
 
+// MountImage mounts a throwaway read-write layer on top of the image
+// referenced by name so that its contents can be read by the builder.
+// The returned function unmounts and releases the layer.
+func (daemon *Daemon) MountImage(name string) (string, func() error, error) {
+	img, err := daemon.GetImage(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	mountID := stringid.GenerateRandomID()
+	rwLayer, err := daemon.layerStore.CreateRWLayer(mountID, img.RootFS.ChainID(), "", nil, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	release := func() error {
+		metadata, err := daemon.layerStore.ReleaseRWLayer(rwLayer)
+		layer.LogReleaseMetadata(metadata)
+		return err
+	}
+
+	root, err := rwLayer.Mount("")
+	if err != nil {
+		release()
+		return "", nil, err
+	}
+
+	return root, func() error {
+		if err := rwLayer.Unmount(); err != nil {
+			return err
+		}
+		return release()
+	}, nil
+}
+
 // GraphDriverName returns the name of the graph driver used by the layer.Store
 func (daemon *Daemon) GraphDriverName() string {
 	return daemon.layerStore.DriverName()
@@ -1363,6 +1448,10 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 		}
 	}
 
+	if p := hostConfig.RestartPolicy; p.UnhealthyThreshold < 0 || p.ResetPeriod < 0 {
+		return nil, fmt.Errorf("Invalid restart policy: unhealthy threshold and reset period can not be negative")
+	}
+
 	// Now do platform-specific verification
 	return verifyPlatformContainerSettings(daemon, hostConfig, config, update)
 }
@@ -1502,19 +1591,169 @@ func (daemon *Daemon) initDiscovery(config *Config) error {
 // - Daemon labels.
 // - Daemon debug log level.
 // - Cluster discovery (reconfigure and restart).
+// - Registry mirrors and insecure registries.
+// - Max concurrent downloads and uploads, and downloads by registry.
+// - Default log driver and log options for the containers.
+// - Authorization plugins, which the API server applies.
+// All the settings are validated before any of them is changed, and
+// the previous labels and debug level are restored if the cluster
+// discovery can't be reloaded, so that the reload is atomic.
+// Once the configuration is reloaded, a daemon `reload` event is emitted
+// with the settings that changed as attributes.
 func (daemon *Daemon) Reload(config *Config) error {
 	daemon.configStore.reloadLock.Lock()
 	defer daemon.configStore.reloadLock.Unlock()
+
+	if err := validateConfiguration(config); err != nil {
+		return err
+	}
+	logConfig, logConfigChanged, err := daemon.reloadedLogConfig(config)
+	if err != nil {
+		return err
+	}
+
+	attributes := make(map[string]string)
+	oldLabels, oldDebug := daemon.configStore.Labels, daemon.configStore.Debug
 	if config.IsValueSet("label") {
+		if !reflect.DeepEqual(daemon.configStore.Labels, config.Labels) {
+			attributes["labels"] = marshalReloadAttribute(config.Labels)
+		}
 		daemon.configStore.Labels = config.Labels
 	}
 	if config.IsValueSet("debug") {
+		if daemon.configStore.Debug != config.Debug {
+			attributes["debug"] = fmt.Sprintf("%t", config.Debug)
+		}
 		daemon.configStore.Debug = config.Debug
 	}
-	return daemon.reloadClusterDiscovery(config)
+	if err := daemon.reloadClusterDiscovery(config, attributes); err != nil {
+		daemon.configStore.Labels, daemon.configStore.Debug = oldLabels, oldDebug
+		return err
+	}
+
+	// None of the settings below can fail once validated
+	if config.IsValueSet("registry-mirrors") || config.IsValueSet("registry-mirrors-by-host") || config.IsValueSet("insecure-registries") {
+		daemon.reloadRegistryConfig(config, attributes)
+	}
+	if config.IsValueSet("max-concurrent-downloads") && config.MaxConcurrentDownloads != nil {
+		if daemon.configStore.MaxConcurrentDownloads == nil || *daemon.configStore.MaxConcurrentDownloads != *config.MaxConcurrentDownloads {
+			attributes["max-concurrent-downloads"] = strconv.Itoa(*config.MaxConcurrentDownloads)
+		}
+		daemon.configStore.MaxConcurrentDownloads = config.MaxConcurrentDownloads
+		if daemon.downloadManager != nil {
+			daemon.downloadManager.SetConcurrency(*config.MaxConcurrentDownloads)
+		}
+	}
+	if config.IsValueSet("registry-max-concurrent-downloads") {
+		if !reflect.DeepEqual(daemon.configStore.RegistryMaxConcurrentDownloads, config.RegistryMaxConcurrentDownloads) {
+			attributes["registry-max-concurrent-downloads"] = marshalReloadAttribute(config.RegistryMaxConcurrentDownloads)
+		}
+		daemon.configStore.RegistryMaxConcurrentDownloads = config.RegistryMaxConcurrentDownloads
+		if daemon.downloadManager != nil {
+			// Validated along with the configuration
+			registryLimits, _ := registryConcurrency(config.RegistryMaxConcurrentDownloads)
+			daemon.downloadManager.SetRegistryConcurrency(registryLimits)
+		}
+	}
+	if config.IsValueSet("max-concurrent-uploads") && config.MaxConcurrentUploads != nil {
+		if daemon.configStore.MaxConcurrentUploads == nil || *daemon.configStore.MaxConcurrentUploads != *config.MaxConcurrentUploads {
+			attributes["max-concurrent-uploads"] = strconv.Itoa(*config.MaxConcurrentUploads)
+		}
+		daemon.configStore.MaxConcurrentUploads = config.MaxConcurrentUploads
+		if daemon.uploadManager != nil {
+			daemon.uploadManager.SetConcurrency(*config.MaxConcurrentUploads)
+		}
+	}
+	if logConfigChanged {
+		attributes["log-driver"] = logConfig.Type
+		attributes["log-opts"] = marshalReloadAttribute(logConfig.Config)
+		daemon.configStore.LogConfig = LogConfig{Type: logConfig.Type, Config: logConfig.Config}
+		daemon.defaultLogConfigLock.Lock()
+		daemon.defaultLogConfig = logConfig
+		daemon.defaultLogConfigLock.Unlock()
+	}
+	if config.IsValueSet("authorization-plugins") {
+		if !reflect.DeepEqual(daemon.configStore.AuthorizationPlugins, config.AuthorizationPlugins) {
+			attributes["authorization-plugins"] = marshalReloadAttribute(config.AuthorizationPlugins)
+		}
+		daemon.configStore.AuthorizationPlugins = config.AuthorizationPlugins
+	}
+
+	daemon.LogDaemonEventWithAttributes("reload", attributes)
+	return nil
 }
 
-func (daemon *Daemon) reloadClusterDiscovery(config *Config) error {
+// reloadedLogConfig returns the default log configuration set by the
+// reloaded configuration, and whether it differs from the current one.
+// When the log driver changes, the options of the previous driver are
+// dropped unless new ones are set.
+func (daemon *Daemon) reloadedLogConfig(config *Config) (containertypes.LogConfig, bool, error) {
+	current := daemon.getDefaultLogConfig()
+	if !config.IsValueSet("log-driver") && !config.IsValueSet("log-opts") {
+		return current, false, nil
+	}
+
+	logConfig := current
+	if config.IsValueSet("log-driver") && config.LogConfig.Type != current.Type {
+		logConfig.Type = config.LogConfig.Type
+		logConfig.Config = nil
+	}
+	if config.IsValueSet("log-opts") {
+		logConfig.Config = config.LogConfig.Config
+	}
+	if logConfig.Type != "none" {
+		if _, err := logger.GetLogDriver(logConfig.Type); err != nil {
+			return current, false, fmt.Errorf("error finding the logging driver: %v", err)
+		}
+	}
+	if err := logger.ValidateLogOpts(logConfig.Type, logConfig.Config); err != nil {
+		return current, false, err
+	}
+	return logConfig, !reflect.DeepEqual(logConfig, current), nil
+}
+
+// reloadRegistryConfig replaces the registry mirrors and insecure registries
+// that are set in the reloaded configuration. They must have been validated.
+func (daemon *Daemon) reloadRegistryConfig(config *Config, attributes map[string]string) {
+	options := daemon.configStore.ServiceOptions
+	if config.IsValueSet("registry-mirrors") {
+		if !reflect.DeepEqual(options.Mirrors, config.Mirrors) {
+			attributes["registry-mirrors"] = marshalReloadAttribute(config.Mirrors)
+		}
+		options.Mirrors = config.Mirrors
+	}
+	if config.IsValueSet("registry-mirrors-by-host") {
+		if !reflect.DeepEqual(options.MirrorsByHost, config.MirrorsByHost) {
+			attributes["registry-mirrors-by-host"] = marshalReloadAttribute(config.MirrorsByHost)
+		}
+		options.MirrorsByHost = config.MirrorsByHost
+	}
+	if config.IsValueSet("insecure-registries") {
+		if !reflect.DeepEqual(options.InsecureRegistries, config.InsecureRegistries) {
+			attributes["insecure-registries"] = marshalReloadAttribute(config.InsecureRegistries)
+		}
+		options.InsecureRegistries = config.InsecureRegistries
+	}
+	if daemon.RegistryService != nil {
+		if err := daemon.RegistryService.Reload(options); err != nil {
+			logrus.Errorf("Failed to reload the registry configuration: %v", err)
+			return
+		}
+	}
+	daemon.configStore.ServiceOptions = options
+}
+
+// marshalReloadAttribute encodes a list or map setting as the JSON value of
+// a reload event attribute.
+func marshalReloadAttribute(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (daemon *Daemon) reloadClusterDiscovery(config *Config, attributes map[string]string) error {
 	var err error
 	newAdvertise := daemon.configStore.ClusterAdvertise
 	newClusterStore := daemon.configStore.ClusterStore
@@ -1556,6 +1795,10 @@ func (daemon *Daemon) reloadClusterDiscovery(config *Config) error {
 	daemon.configStore.ClusterOpts = config.ClusterOpts
 	daemon.configStore.ClusterAdvertise = newAdvertise
 
+	attributes["cluster-store"] = newClusterStore
+	attributes["cluster-store-opts"] = marshalReloadAttribute(config.ClusterOpts)
+	attributes["cluster-advertise"] = newAdvertise
+
 	if daemon.netController == nil {
 		return nil
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/pkg/discovery"
 	_ "github.com/docker/docker/pkg/discovery/memory"
 	"github.com/docker/docker/pkg/registrar"
@@ -18,6 +19,7 @@ import (
 	"github.com/docker/docker/volume/local"
 	"github.com/docker/docker/volume/store"
 	containertypes "github.com/docker/engine-api/types/container"
+	eventtypes "github.com/docker/engine-api/types/events"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -364,6 +366,107 @@ func TestDaemonReloadNotAffectOthers(t *testing.T) {
 	}
 }
 
+func TestDaemonReloadEvent(t *testing.T) {
+	daemon := &Daemon{
+		ID:            "daemon-id",
+		EventsService: events.New(),
+	}
+	daemon.configStore = &Config{
+		CommonConfig: CommonConfig{
+			Labels: []string{"foo:bar"},
+			Debug:  true,
+		},
+	}
+
+	valuesSets := make(map[string]interface{})
+	valuesSets["label"] = "foo:baz"
+	valuesSets["debug"] = true
+	newConfig := &Config{
+		CommonConfig: CommonConfig{
+			Labels:    []string{"foo:baz"},
+			Debug:     true,
+			valuesSet: valuesSets,
+		},
+	}
+
+	_, l, cancel := daemon.EventsService.Subscribe()
+	defer cancel()
+	if err := daemon.Reload(newConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("failed to get the reload event in time")
+	case e := <-l:
+		ev := e.(eventtypes.Message)
+		if ev.Type != eventtypes.DaemonEventType || ev.Action != "reload" || ev.Actor.ID != "daemon-id" {
+			t.Fatalf("expected a daemon reload event, got %v", ev)
+		}
+		if labels := ev.Actor.Attributes["labels"]; labels != `["foo:baz"]` {
+			t.Fatalf("expected the new labels in the event, got %q", labels)
+		}
+		if _, ok := ev.Actor.Attributes["debug"]; ok {
+			t.Fatalf("expected the unchanged debug setting not to be in the event, got %v", ev.Actor.Attributes)
+		}
+	}
+}
+
+func TestDaemonReloadLogConfigAndConcurrency(t *testing.T) {
+	daemon := &Daemon{
+		defaultLogConfig: containertypes.LogConfig{
+			Type:   "json-file",
+			Config: map[string]string{"max-size": "10m"},
+		},
+	}
+	daemon.configStore = &Config{
+		CommonConfig: CommonConfig{
+			Labels: []string{"foo:bar"},
+		},
+	}
+
+	// An invalid setting must not change anything
+	invalid := &Config{
+		CommonConfig: CommonConfig{
+			Labels:    []string{"foo:baz"},
+			LogConfig: LogConfig{Config: map[string]string{"unknown": "opt"}},
+			valuesSet: map[string]interface{}{"label": "foo:baz", "log-opts": "unknown=opt"},
+		},
+	}
+	if err := daemon.Reload(invalid); err == nil {
+		t.Fatal("expected an error reloading invalid log options")
+	}
+	if label := daemon.configStore.Labels[0]; label != "foo:bar" {
+		t.Fatalf("expected the labels not to change, got %s", label)
+	}
+
+	downloads, uploads := 10, 1
+	newConfig := &Config{
+		CommonConfig: CommonConfig{
+			LogConfig:              LogConfig{Type: "none"},
+			MaxConcurrentDownloads: &downloads,
+			MaxConcurrentUploads:   &uploads,
+			valuesSet: map[string]interface{}{
+				"log-driver":               "none",
+				"max-concurrent-downloads": downloads,
+				"max-concurrent-uploads":   uploads,
+			},
+		},
+	}
+	if err := daemon.Reload(newConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	logConfig := daemon.getDefaultLogConfig()
+	if logConfig.Type != "none" || len(logConfig.Config) != 0 {
+		t.Fatalf("expected the none log driver without options, got %v", logConfig)
+	}
+	if *daemon.configStore.MaxConcurrentDownloads != 10 || *daemon.configStore.MaxConcurrentUploads != 1 {
+		t.Fatalf("expected the concurrency to be reloaded, got %d downloads and %d uploads",
+			*daemon.configStore.MaxConcurrentDownloads, *daemon.configStore.MaxConcurrentUploads)
+	}
+}
+
 func TestDaemonDiscoveryReload(t *testing.T) {
 	daemon := &Daemon{}
 	daemon.configStore = &Config{
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+)
+
+// SystemDiskUsage returns information about the disk space used by the
+// daemon's images, containers and volumes.
+func (daemon *Daemon) SystemDiskUsage() (*types.DiskUsage, error) {
+	// Retrieve all the containers, with the size of their RW layer
+	allContainers, err := daemon.Containers(&types.ContainerListOptions{
+		Size: true,
+		All:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all top images with their shared size and container count
+	allImages, err := daemon.Images("", "", false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all the volumes, with their size if they are local ones
+	vols, _, err := daemon.volumes.List()
+	if err != nil {
+		return nil, err
+	}
+	allVolumes := make([]*types.Volume, 0, len(vols))
+	for _, v := range vols {
+		tv := volumeToAPIType(v)
+		tv.UsageData = &types.VolumeUsageData{
+			Size:     -1,
+			RefCount: len(daemon.volumes.Refs(v)),
+		}
+		if v.DriverName() == volume.DefaultDriverName {
+			size, err := directory.Size(v.Path())
+			if err != nil {
+				logrus.Warnf("failed to determine size of volume %v: %v", v.Name(), err)
+			} else {
+				tv.UsageData.Size = size
+			}
+		}
+		allVolumes = append(allVolumes, tv)
+	}
+
+	// Get the size of all the layers, each one counted once
+	var layersSize int64
+	seen := make(map[layer.ChainID]struct{})
+	for _, img := range daemon.imageStore.Map() {
+		for _, chainID := range layerChain(img) {
+			if _, ok := seen[chainID]; ok {
+				continue
+			}
+			seen[chainID] = struct{}{}
+			size, err := daemon.layerDiffSize(chainID)
+			if err != nil {
+				return nil, err
+			}
+			layersSize += size
+		}
+	}
+
+	return &types.DiskUsage{
+		LayersSize: layersSize,
+		Images:     allImages,
+		Containers: allContainers,
+		Volumes:    allVolumes,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"os"
 	"strings"
 
 	"github.com/docker/docker/container"
@@ -77,6 +78,21 @@ func (daemon *Daemon) LogNetworkEventWithAttributes(nw libnetwork.Network, actio
 	daemon.EventsService.Log(action, events.NetworkEventType, actor)
 }
 
+// LogDaemonEventWithAttributes generates an event related to the daemon itself with specific given attributes.
+func (daemon *Daemon) LogDaemonEventWithAttributes(action string, attributes map[string]string) {
+	if daemon.EventsService == nil {
+		return
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		attributes["name"] = hostname
+	}
+	actor := events.Actor{
+		ID:         daemon.ID,
+		Attributes: attributes,
+	}
+	daemon.EventsService.Log(action, events.DaemonEventType, actor)
+}
+
 // copyAttributes guarantees that labels are not mutated by event triggers.
 func copyAttributes(attributes, labels map[string]string) {
 	if labels == nil {
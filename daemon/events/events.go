@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/pubsub"
 	eventtypes "github.com/docker/engine-api/types/events"
 )
@@ -15,9 +16,10 @@ const (
 
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
 }
 
 // New returns new *Events instance
@@ -28,6 +30,14 @@ func New() *Events {
 	}
 }
 
+// SetJournal makes the events be recorded in the journal, which is then
+// used instead of the in-memory buffer to replay past events.
+func (e *Events) SetJournal(j *Journal) {
+	e.mu.Lock()
+	e.journal = j
+	e.mu.Unlock()
+}
+
 // Subscribe adds new listener to events, returns slice of 64 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -45,10 +55,10 @@ func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}, func()) {
 	return current, l, cancel
 }
 
-// SubscribeTopic adds new listener to events, returns slice of 64 stored
-// last events, a channel in which you can expect new events (in form
-// of interface{}, so you need type assertion).
-func (e *Events) SubscribeTopic(since, sinceNano int64, ef *Filter) ([]eventtypes.Message, chan interface{}) {
+// SubscribeTopic adds new listener to events, returns the stored events
+// emitted between since and until, a channel in which you can expect new
+// events (in form of interface{}, so you need type assertion).
+func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtypes.Message, chan interface{}) {
 	e.mu.Lock()
 
 	var topic func(m interface{}) bool
@@ -56,7 +66,7 @@ func (e *Events) SubscribeTopic(since, sinceNano int64, ef *Filter) ([]eventtype
 		topic = func(m interface{}) bool { return ef.Include(m.(eventtypes.Message)) }
 	}
 
-	buffered := e.loadBufferedEvents(since, sinceNano, topic)
+	buffered := e.loadBufferedEvents(since, until, topic)
 
 	var ch chan interface{}
 	if topic != nil {
@@ -99,6 +109,11 @@ func (e *Events) Log(action, eventType string, actor eventtypes.Actor) {
 	}
 
 	e.mu.Lock()
+	if e.journal != nil {
+		if err := e.journal.Write(jm); err != nil {
+			logrus.Warnf("failed to write event to journal: %v", err)
+		}
+	}
 	if len(e.events) == cap(e.events) {
 		// discard oldest event
 		copy(e.events, e.events[1:])
@@ -115,25 +130,42 @@ func (e *Events) SubscribersCount() int {
 	return e.pub.Len()
 }
 
-// loadBufferedEvents iterates over the cached events in the buffer
-// and returns those that were emitted before a specific date.
-// The date is splitted in two values:
-//   - the `since` argument is a date timestamp without nanoseconds, or -1 to return an empty slice.
-//   - the `sinceNano` argument is the nanoseconds offset from the timestamp.
-// It uses `time.Unix(seconds, nanoseconds)` to generate a valid date with those two first arguments.
-// It filters those buffered messages with a topic function if it's not nil, otherwise it adds all messages.
-func (e *Events) loadBufferedEvents(since, sinceNano int64, topic func(interface{}) bool) []eventtypes.Message {
+// loadBufferedEvents returns the stored events that were emitted between
+// two dates, oldest first. It returns an empty slice when neither `since`
+// nor `until` are set, and there is no upper bound when only `until` is
+// zero. The events are read from the journal when there is one, otherwise
+// from the in-memory buffer.
+// It filters those messages with a topic function if it's not nil,
+// otherwise it adds all messages.
+func (e *Events) loadBufferedEvents(since, until time.Time, topic func(interface{}) bool) []eventtypes.Message {
 	var buffered []eventtypes.Message
-	if since == -1 {
+	if since.IsZero() && until.IsZero() {
 		return buffered
 	}
 
-	sinceNanoUnix := time.Unix(since, sinceNano).UnixNano()
+	if e.journal != nil {
+		journaled, err := e.journal.Read(since, until, topic)
+		if err == nil {
+			return journaled
+		}
+		logrus.Warnf("failed to read events journal, using the buffered events: %v", err)
+	}
+
+	var sinceNanoUnix, untilNanoUnix int64
+	if !since.IsZero() {
+		sinceNanoUnix = since.UnixNano()
+	}
+	if !until.IsZero() {
+		untilNanoUnix = until.UnixNano()
+	}
 	for i := len(e.events) - 1; i >= 0; i-- {
 		ev := e.events[i]
 		if ev.TimeNano < sinceNanoUnix {
 			break
 		}
+		if untilNanoUnix > 0 && ev.TimeNano > untilNanoUnix {
+			continue
+		}
 		if topic == nil || topic(ev) {
 			buffered = append([]eventtypes.Message{ev}, buffered...)
 		}
@@ -189,8 +189,29 @@ func TestLoadBufferedEvents(t *testing.T) {
 		events: buffered,
 	}
 
-	out := events.loadBufferedEvents(since, sinceNano, nil)
+	out := events.loadBufferedEvents(time.Unix(since, sinceNano), time.Time{}, nil)
 	if len(out) != 1 {
 		t.Fatalf("expected 1 message, got %d: %v", len(out), out)
 	}
 }
+
+func TestLoadBufferedEventsUntil(t *testing.T) {
+	base := time.Unix(1457364483, 0)
+	var buffered []events.Message
+	for i := 0; i < 5; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		buffered = append(buffered, events.Message{Action: fmt.Sprintf("action_%d", i), Time: ts.Unix(), TimeNano: ts.UnixNano()})
+	}
+
+	events := &Events{
+		events: buffered,
+	}
+
+	out := events.loadBufferedEvents(base.Add(time.Second), base.Add(3*time.Second), nil)
+	if len(out) != 3 {
+		t.Fatalf("expected 3 messages, got %d: %v", len(out), out)
+	}
+	if out[0].Action != "action_1" || out[2].Action != "action_3" {
+		t.Fatalf("unexpected messages: %v", out)
+	}
+}
@@ -0,0 +1,174 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	eventtypes "github.com/docker/engine-api/types/events"
+)
+
+var errJournalClosed = errors.New("events journal is closed")
+
+// Journal is an on-disk log of events that survives daemon restarts.
+// Events are stored one JSON message per line. Once the current file
+// reaches half of the maximum size it is rotated, so that the journal
+// never uses more than maxSize bytes on disk.
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	f       *os.File
+	size    int64
+}
+
+// NewJournal opens the journal at path, creating it if it doesn't exist.
+// New events are appended to the ones logged by previous daemons.
+func NewJournal(path string, maxSize int64) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	j := &Journal{
+		path:    path,
+		maxSize: maxSize,
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	j.f = f
+	j.size = fi.Size()
+	return nil
+}
+
+// rotate moves the current file aside, replacing the previously rotated
+// one, and starts a new file.
+func (j *Journal) rotate() error {
+	if err := j.f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(j.path, j.path+".1"); err != nil {
+		return err
+	}
+	return j.open()
+}
+
+// Write appends the event to the journal.
+func (j *Journal) Write(ev eventtypes.Message) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return errJournalClosed
+	}
+	if j.size > 0 && j.size+int64(len(b)) > j.maxSize/2 {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := j.f.Write(b)
+	j.size += int64(n)
+	return err
+}
+
+// Read returns the events logged between since and until, oldest first.
+// A zero until means there is no upper bound. The events are filtered
+// with the topic function if it's not nil.
+func (j *Journal) Read(since, until time.Time, topic func(interface{}) bool) ([]eventtypes.Message, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var (
+		sinceNano = since.UnixNano()
+		untilNano int64
+		out       []eventtypes.Message
+	)
+	if !until.IsZero() {
+		untilNano = until.UnixNano()
+	}
+
+	// The rotated file holds the oldest events
+	for _, p := range []string{j.path + ".1", j.path} {
+		var (
+			done bool
+			err  error
+		)
+		out, done, err = readJournalFile(p, sinceNano, untilNano, topic, out)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+	return out, nil
+}
+
+// readJournalFile appends the matching events of a journal file to out. It
+// returns true when an event logged after untilNano was found, as there is
+// no need to read any further.
+func readJournalFile(p string, sinceNano, untilNano int64, topic func(interface{}) bool, out []eventtypes.Message) ([]eventtypes.Message, bool, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return out, false, nil
+		}
+		return out, false, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1024*1024)
+	for s.Scan() {
+		var ev eventtypes.Message
+		if err := json.Unmarshal(s.Bytes(), &ev); err != nil {
+			// The last line may have been cut short by a crash
+			logrus.Debugf("skipping invalid event in journal %s: %v", p, err)
+			continue
+		}
+		if ev.TimeNano < sinceNano {
+			continue
+		}
+		if untilNano > 0 && ev.TimeNano > untilNano {
+			return out, true, nil
+		}
+		if topic == nil || topic(ev) {
+			out = append(out, ev)
+		}
+	}
+	return out, false, s.Err()
+}
+
+// Close closes the journal. Events written after it are dropped.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
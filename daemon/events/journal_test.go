@@ -0,0 +1,113 @@
+package events
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types/events"
+	"github.com/docker/engine-api/types/filters"
+)
+
+func TestJournalReplay(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "events", "events.log")
+
+	j, err := NewJournal(path, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := New()
+	e.SetJournal(j)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		e.Log(fmt.Sprintf("action_%d", i), events.ContainerEventType, events.Actor{ID: "cont"})
+	}
+	e.Log("create", events.VolumeEventType, events.Actor{ID: "vol"})
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new daemon replays the events logged by the previous one
+	j, err = NewJournal(path, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	e = New()
+	e.SetJournal(j)
+
+	msgs, l := e.SubscribeTopic(start, time.Time{}, nil)
+	e.Evict(l)
+	if len(msgs) != 101 {
+		t.Fatalf("Must be 101 events, got %d", len(msgs))
+	}
+	if msgs[0].Action != "action_0" {
+		t.Fatalf("First action is %s, must be action_0", msgs[0].Action)
+	}
+
+	args := filters.NewArgs()
+	args.Add("type", events.VolumeEventType)
+	msgs, l = e.SubscribeTopic(start, time.Time{}, NewFilter(args))
+	e.Evict(l)
+	if len(msgs) != 1 || msgs[0].Actor.ID != "vol" {
+		t.Fatalf("Expected the volume event only, got %v", msgs)
+	}
+
+	msgs, l = e.SubscribeTopic(time.Time{}, time.Unix(0, msgs[0].TimeNano-1), nil)
+	e.Evict(l)
+	if len(msgs) != 100 {
+		t.Fatalf("Must be 100 events before the volume one, got %d", len(msgs))
+	}
+}
+
+func TestJournalRotate(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "events.log")
+
+	maxSize := int64(4096)
+	j, err := NewJournal(path, maxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	for i := 0; i < 200; i++ {
+		now := time.Now()
+		ev := events.Message{Action: fmt.Sprintf("action_%d", i), Time: now.Unix(), TimeNano: now.UnixNano()}
+		if err := j.Write(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var size int64
+	for _, p := range []string{path, path + ".1"} {
+		fi, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += fi.Size()
+	}
+	if size > maxSize {
+		t.Fatalf("journal uses %d bytes, more than %d", size, maxSize)
+	}
+
+	msgs, err := j.Read(time.Unix(0, 0), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) == 0 || msgs[len(msgs)-1].Action != "action_199" {
+		t.Fatalf("expected the latest events to be kept, got %v", msgs)
+	}
+}
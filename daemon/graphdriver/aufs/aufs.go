@@ -76,6 +76,7 @@ type Driver struct {
 	gidMaps       []idtools.IDMap
 	pathCacheLock sync.Mutex
 	pathCache     map[string]string
+	naiveDiff     graphdriver.Driver
 }
 
 // Init returns a new AUFS driver.
@@ -113,6 +114,7 @@ func Init(root string, options []string, uidMaps, gidMaps []idtools.IDMap) (grap
 		gidMaps:   gidMaps,
 		pathCache: make(map[string]string),
 	}
+	a.naiveDiff = graphdriver.NewNaiveDiffDriver(a, uidMaps, gidMaps)
 
 	rootUID, rootGID, err := idtools.GetRootUIDGID(uidMaps, gidMaps)
 	if err != nil {
@@ -359,6 +361,12 @@ func (a *Driver) Put(id string) error {
 // Diff produces an archive of the changes between the specified
 // layer and its parent layer which may be "".
 func (a *Driver) Diff(id, parent string) (archive.Archive, error) {
+	if !a.isParent(id, parent) {
+		// The diff is against an ancestor further up the chain, so compare
+		// the mounted filesystems instead of reading our own diff directory.
+		return a.naiveDiff.Diff(id, parent)
+	}
+
 	// AUFS doesn't need the parent layer to produce a diff.
 	return archive.TarWithOptions(path.Join(a.rootPath(), "diff", id), &archive.TarOptions{
 		Compression:     archive.Uncompressed,
@@ -368,6 +376,16 @@ func (a *Driver) Diff(id, parent string) (archive.Archive, error) {
 	})
 }
 
+// isParent returns whether parent is the direct parent of the layer id,
+// which is the only case in which the diff directory alone holds the diff.
+func (a *Driver) isParent(id, parent string) bool {
+	parents, _ := getParentIds(a.rootPath(), id)
+	if parent == "" && len(parents) > 0 {
+		return false
+	}
+	return !(len(parents) > 0 && parent != parents[0])
+}
+
 type fileGetNilCloser struct {
 	storage.FileGetter
 }
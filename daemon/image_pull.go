@@ -97,6 +97,7 @@ func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
 		DownloadManager:  daemon.downloadManager,
+		PartialBlobStore: daemon.partialBlobStore,
 	}
 
 	err := distribution.Pull(ctx, ref, imagePullConfig)
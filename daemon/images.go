@@ -1,9 +1,12 @@
 package daemon
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
+	"runtime"
 	"sort"
+	"time"
 
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -34,12 +37,14 @@ func (daemon *Daemon) Map() map[image.ID]*image.Image {
 // of filter arguments which will be interpreted by api/types/filters.
 // filter is a shell glob string applied to repository names. The argument
 // named all controls whether all images in the graph are filtered, or just
-// the heads.
-func (daemon *Daemon) Images(filterArgs, filter string, all bool) ([]*types.Image, error) {
+// the heads. withExtraAttrs also computes the size shared with the other
+// returned images and the number of containers using each image.
+func (daemon *Daemon) Images(filterArgs, filter string, all bool, withExtraAttrs bool) ([]*types.Image, error) {
 	var (
 		allImages    map[image.ID]*image.Image
 		err          error
 		danglingOnly = false
+		imagesMap    = make(map[*image.Image]*types.Image)
 	)
 
 	imageFilters, err := filters.FromParam(filterArgs)
@@ -141,6 +146,13 @@ func (daemon *Daemon) Images(filterArgs, filter string, all bool) ([]*types.Imag
 		}
 
 		images = append(images, newImage)
+		imagesMap[img] = newImage
+	}
+
+	if withExtraAttrs {
+		if err := daemon.setImagesUsage(imagesMap); err != nil {
+			return nil, err
+		}
 	}
 
 	sort.Sort(sort.Reverse(byCreated(images)))
@@ -148,6 +160,164 @@ func (daemon *Daemon) Images(filterArgs, filter string, all bool) ([]*types.Imag
 	return images, nil
 }
 
+// SquashImage creates a new image with the diff of the specified image and
+// the specified parent. The new image has the layers of the parent plus a
+// single layer holding everything that changed since. The history entries of
+// the squashed image are kept, but marked as not having a layer of their own.
+func (daemon *Daemon) SquashImage(id, parent string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("squashing images is not supported on Windows")
+	}
+
+	img, err := daemon.imageStore.Get(image.ID(id))
+	if err != nil {
+		return "", err
+	}
+
+	parentImg := &image.Image{RootFS: image.NewRootFS()}
+	if parent != "" {
+		parentImg, err = daemon.imageStore.Get(image.ID(parent))
+		if err != nil {
+			return "", fmt.Errorf("error getting specified parent layer: %v", err)
+		}
+	}
+	parentChainID := parentImg.RootFS.ChainID()
+
+	// Nothing was added on top of the parent, so there is nothing to squash
+	if img.RootFS.ChainID() == parentChainID {
+		return id, nil
+	}
+
+	l, err := daemon.layerStore.Get(img.RootFS.ChainID())
+	if err != nil {
+		return "", fmt.Errorf("error getting image layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	ts, err := l.TarStreamFrom(parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error getting tar stream to parent: %v", err)
+	}
+	defer ts.Close()
+
+	newL, err := daemon.layerStore.Register(ts, parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error registering layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, newL)
+
+	newImage := *img
+	newImage.Parent = image.ID(parent)
+	rootFS := *parentImg.RootFS
+	rootFS.DiffIDs = append([]layer.DiffID(nil), parentImg.RootFS.DiffIDs...)
+	newImage.RootFS = &rootFS
+
+	newImage.History = make([]image.History, 0, len(img.History)+1)
+	for i, h := range img.History {
+		if i >= len(parentImg.History) {
+			h.EmptyLayer = true
+		}
+		newImage.History = append(newImage.History, h)
+	}
+
+	now := time.Now().UTC()
+	h := image.History{
+		Created:    now,
+		EmptyLayer: true,
+	}
+	if parent != "" {
+		h.Comment = fmt.Sprintf("merge %s to %s", id, parent)
+	} else {
+		h.Comment = fmt.Sprintf("create new from %s", id)
+	}
+	if diffID := newL.DiffID(); diffID != layer.DigestSHA256EmptyTar {
+		h.EmptyLayer = false
+		newImage.RootFS.Append(diffID)
+	}
+	newImage.History = append(newImage.History, h)
+	newImage.Created = now
+
+	b, err := json.Marshal(&newImage)
+	if err != nil {
+		return "", err
+	}
+
+	newImgID, err := daemon.imageStore.Create(b)
+	if err != nil {
+		return "", err
+	}
+
+	if parent != "" {
+		if err := daemon.imageStore.SetParent(newImgID, image.ID(parent)); err != nil {
+			return "", err
+		}
+	}
+
+	return newImgID.String(), nil
+}
+
+// setImagesUsage fills in the number of containers using each image, and the
+// size of the layers each image shares with the other images of the map.
+func (daemon *Daemon) setImagesUsage(images map[*image.Image]*types.Image) error {
+	containers := make(map[image.ID]int64)
+	for _, c := range daemon.List() {
+		containers[c.ImageID]++
+	}
+
+	layerRefs := make(map[layer.ChainID]int)
+	for img := range images {
+		for _, chainID := range layerChain(img) {
+			layerRefs[chainID]++
+		}
+	}
+
+	sizes := make(map[layer.ChainID]int64)
+	for img, newImage := range images {
+		newImage.Containers = containers[img.ID()]
+		for _, chainID := range layerChain(img) {
+			if layerRefs[chainID] < 2 {
+				continue
+			}
+			size, ok := sizes[chainID]
+			if !ok {
+				var err error
+				if size, err = daemon.layerDiffSize(chainID); err != nil {
+					return err
+				}
+				sizes[chainID] = size
+			}
+			newImage.SharedSize += size
+		}
+	}
+	return nil
+}
+
+// layerChain returns the ChainID of every layer of the image, from the
+// bottom one up.
+func layerChain(img *image.Image) []layer.ChainID {
+	if img.RootFS == nil {
+		return nil
+	}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	chain := make([]layer.ChainID, 0, len(img.RootFS.DiffIDs))
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		chain = append(chain, rootFS.ChainID())
+	}
+	return chain
+}
+
+// layerDiffSize returns the size of a single layer, without its parents.
+func (daemon *Daemon) layerDiffSize(chainID layer.ChainID) (int64, error) {
+	l, err := daemon.layerStore.Get(chainID)
+	if err != nil {
+		return 0, err
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+	return l.DiffSize()
+}
+
 func newImage(image *image.Image, size int64) *types.Image {
 	newImage := new(types.Image)
 	newImage.ParentID = image.Parent.String()
@@ -110,12 +110,22 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 
 	// we need this trick to preserve empty log driver, so
 	// container will use daemon defaults even if daemon changes them
+	defaultLogConfig := daemon.getDefaultLogConfig()
 	if hostConfig.LogConfig.Type == "" {
-		hostConfig.LogConfig.Type = daemon.defaultLogConfig.Type
+		hostConfig.LogConfig.Type = defaultLogConfig.Type
 	}
 
 	if len(hostConfig.LogConfig.Config) == 0 {
-		hostConfig.LogConfig.Config = daemon.defaultLogConfig.Config
+		hostConfig.LogConfig.Config = defaultLogConfig.Config
+	}
+
+	var containerHealth *types.Health
+	if container.State.Health != nil {
+		containerHealth = &types.Health{
+			Status:        container.State.Health.Status,
+			FailingStreak: container.State.Health.FailingStreak,
+			Log:           append([]*types.HealthcheckResult{}, container.State.Health.Log...),
+		}
 	}
 
 	containerState := &types.ContainerState{
@@ -130,24 +140,27 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 		Error:      container.State.Error,
 		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
 		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
+		Health:     containerHealth,
 	}
 
 	contJSONBase := &types.ContainerJSONBase{
-		ID:           container.ID,
-		Created:      container.Created.Format(time.RFC3339Nano),
-		Path:         container.Path,
-		Args:         container.Args,
-		State:        containerState,
-		Image:        container.ImageID.String(),
-		LogPath:      container.LogPath,
-		Name:         container.Name,
-		RestartCount: container.RestartCount,
-		Driver:       container.Driver,
-		MountLabel:   container.MountLabel,
-		ProcessLabel: container.ProcessLabel,
-		ExecIDs:      container.GetExecIDs(),
-		HostConfig:   &hostConfig,
+		ID:                container.ID,
+		Created:           container.Created.Format(time.RFC3339Nano),
+		Path:              container.Path,
+		Args:              container.Args,
+		State:             containerState,
+		Image:             container.ImageID.String(),
+		LogPath:           container.LogPath,
+		Name:              container.Name,
+		RestartCount:      container.RestartCount,
+		LastRestartReason: container.LastRestartReason,
+		Driver:            container.Driver,
+		MountLabel:        container.MountLabel,
+		ProcessLabel:      container.ProcessLabel,
+		ExecIDs:           container.GetExecIDs(),
+		HostConfig:        &hostConfig,
 	}
+	contJSONBase.LogDroppedMessages = logDroppedMessages(container)
 
 	var (
 		sizeRw     int64
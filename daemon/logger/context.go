@@ -60,6 +60,16 @@ func (ctx *Context) ExtraAttributes(keyMod func(string) string) map[string]strin
 	return extra
 }
 
+// LogAttributes returns the attributes added to the log messages of the
+// container, nil if none are selected.
+func (ctx *Context) LogAttributes() LogAttributes {
+	attrs := ctx.ExtraAttributes(nil)
+	if len(attrs) == 0 {
+		return nil
+	}
+	return LogAttributes(attrs)
+}
+
 // Hostname returns the hostname from the underlying OS.
 func (ctx *Context) Hostname() (string, error) {
 	hostname, err := os.Hostname()
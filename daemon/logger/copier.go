@@ -1,17 +1,21 @@
 package logger
 
 import (
-	"bufio"
 	"bytes"
 	"io"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Sirupsen/logrus"
 )
 
+// MaxLineSize is the maximum size of the line of a message. The longer lines
+// are split in several messages, all of them partial but the last one.
+const MaxLineSize = 16 * 1024
+
 // Copier can copy logs from specified sources to Logger and attach
-// ContainerID and Timestamp.
+// ContainerID, Timestamp and the attributes of the container.
 // Writes are concurrent, so you need implement some sync in your logger
 type Copier struct {
 	// cid is the container id for which we are copying logs
@@ -19,16 +23,18 @@ type Copier struct {
 	// srcs is map of name -> reader pairs, for example "stdout", "stderr"
 	srcs     map[string]io.Reader
 	dst      Logger
+	attrs    LogAttributes
 	copyJobs sync.WaitGroup
 	closed   chan struct{}
 }
 
 // NewCopier creates a new Copier
-func NewCopier(cid string, srcs map[string]io.Reader, dst Logger) *Copier {
+func NewCopier(cid string, srcs map[string]io.Reader, dst Logger, attrs LogAttributes) *Copier {
 	return &Copier{
 		cid:    cid,
 		srcs:   srcs,
 		dst:    dst,
+		attrs:  attrs,
 		closed: make(chan struct{}),
 	}
 }
@@ -43,25 +49,48 @@ func (c *Copier) Run() {
 
 func (c *Copier) copySrc(name string, src io.Reader) {
 	defer c.copyJobs.Done()
-	reader := bufio.NewReader(src)
+	buf := make([]byte, MaxLineSize)
+	// n is the number of bytes in buf, of which the first scanned ones have
+	// no newline.
+	n, scanned := 0, 0
 
 	for {
 		select {
 		case <-c.closed:
 			return
 		default:
-			line, err := reader.ReadBytes('\n')
-			line = bytes.TrimSuffix(line, []byte{'\n'})
+			read, err := src.Read(buf[n:])
+			n += read
 
-			// ReadBytes can return full or partial output even when it failed.
-			// e.g. it can return a full entry and EOF.
-			if err == nil || len(line) > 0 {
-				if logErr := c.dst.Log(&Message{ContainerID: c.cid, Line: line, Source: name, Timestamp: time.Now().UTC()}); logErr != nil {
-					logrus.Errorf("Failed to log msg %q for logger %s: %s", line, c.dst.Name(), logErr)
+			// Log the complete lines
+			p := 0
+			for {
+				i := bytes.IndexByte(buf[scanned:n], '\n')
+				if i < 0 {
+					break
+				}
+				select {
+				case <-c.closed:
+					return
+				default:
 				}
+				c.log(name, buf[p:scanned+i], false)
+				p = scanned + i + 1
+				scanned = p
 			}
+			if p == 0 && n == len(buf) {
+				// The buffer is full of a single line, log a part of it
+				p = partialSize(buf)
+				c.log(name, buf[:p], true)
+			}
+			n = copy(buf, buf[p:n])
+			scanned = n
 
 			if err != nil {
+				// Log what's left of the output, even without a newline
+				if n > 0 {
+					c.log(name, buf[:n], false)
+				}
 				if err != io.EOF {
 					logrus.Errorf("Error scanning log stream: %s", err)
 				}
@@ -71,6 +100,36 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	}
 }
 
+// log logs a copy of the line, as the buffer it's read into is reused.
+func (c *Copier) log(name string, line []byte, partial bool) {
+	msg := &Message{
+		ContainerID: c.cid,
+		Line:        append([]byte(nil), line...),
+		Source:      name,
+		Timestamp:   time.Now().UTC(),
+		Attrs:       c.attrs,
+		Partial:     partial,
+	}
+	if err := c.dst.Log(msg); err != nil {
+		logrus.Errorf("Failed to log msg %q for logger %s: %s", line, c.dst.Name(), err)
+	}
+}
+
+// partialSize returns the size of the part of a full buffer to log as a
+// partial message, so that a UTF-8 encoded character at the end of the
+// buffer isn't split between two messages.
+func partialSize(buf []byte) int {
+	for i := len(buf) - 1; i >= 0 && i >= len(buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			if !utf8.FullRune(buf[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(buf)
+}
+
 // Wait waits until all copying is done
 func (c *Copier) Wait() {
 	c.copyJobs.Wait()
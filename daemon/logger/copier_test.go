@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -56,12 +58,13 @@ func TestCopier(t *testing.T) {
 	jsonLog := &TestLoggerJSON{Encoder: json.NewEncoder(&jsonBuf)}
 
 	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	attrs := LogAttributes{"rack": "101"}
 	c := NewCopier(cid,
 		map[string]io.Reader{
 			"stdout": &stdout,
 			"stderr": &stderr,
 		},
-		jsonLog)
+		jsonLog, attrs)
 	c.Run()
 	wait := make(chan struct{})
 	go func() {
@@ -88,6 +91,9 @@ func TestCopier(t *testing.T) {
 		if msg.ContainerID != cid {
 			t.Fatalf("Wrong ContainerID: %q, expected %q", msg.ContainerID, cid)
 		}
+		if !reflect.DeepEqual(msg.Attrs, attrs) {
+			t.Fatalf("Wrong Attrs: %q, expected %q", msg.Attrs, attrs)
+		}
 		if msg.Source == "stdout" {
 			if string(msg.Line) != stdoutLine {
 				t.Fatalf("Wrong Line: %q, expected %q", msg.Line, stdoutLine)
@@ -115,7 +121,7 @@ func TestCopierSlow(t *testing.T) {
 	jsonLog := &TestLoggerJSON{Encoder: json.NewEncoder(&jsonBuf), delay: 100 * time.Millisecond}
 
 	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
-	c := NewCopier(cid, map[string]io.Reader{"stdout": &stdout}, jsonLog)
+	c := NewCopier(cid, map[string]io.Reader{"stdout": &stdout}, jsonLog, nil)
 	c.Run()
 	wait := make(chan struct{})
 	go func() {
@@ -130,3 +136,38 @@ func TestCopierSlow(t *testing.T) {
 	case <-wait:
 	}
 }
+
+func TestCopierLongLines(t *testing.T) {
+	long := strings.Repeat("a", 2*MaxLineSize+10)
+	// The last character is split by the end of the buffer
+	accented := strings.Repeat("a", MaxLineSize-1) + "é"
+	stdout := bytes.NewBufferString(long + "\n" + accented + "\nend")
+
+	var jsonBuf bytes.Buffer
+	jsonLog := &TestLoggerJSON{Encoder: json.NewEncoder(&jsonBuf)}
+	c := NewCopier("cid", map[string]io.Reader{"stdout": stdout}, jsonLog, nil)
+	c.Run()
+	c.Wait()
+
+	expected := []Message{
+		{Line: []byte(long[:MaxLineSize]), Partial: true},
+		{Line: []byte(long[MaxLineSize : 2*MaxLineSize]), Partial: true},
+		{Line: []byte(long[2*MaxLineSize:])},
+		{Line: []byte(accented[:MaxLineSize-1]), Partial: true},
+		{Line: []byte("é")},
+		{Line: []byte("end")},
+	}
+	dec := json.NewDecoder(&jsonBuf)
+	for i, e := range expected {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatalf("expected %d messages, got %d: %v", len(expected), i, err)
+		}
+		if string(msg.Line) != string(e.Line) || msg.Partial != e.Partial {
+			t.Fatalf("expected message %d to be %q (partial %v), got %q (partial %v)", i, e.Line, e.Partial, msg.Line, msg.Partial)
+		}
+	}
+	if dec.More() {
+		t.Fatal("unexpected messages after the last line")
+	}
+}
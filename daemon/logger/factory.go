@@ -3,6 +3,9 @@ package logger
 import (
 	"fmt"
 	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/go-units"
 )
 
 // Creator builds a logging driver instance with given context.
@@ -42,11 +45,17 @@ func (lf *logdriverFactory) registerLogOptValidator(name string, l LogOptValidat
 
 func (lf *logdriverFactory) get(name string) (Creator, error) {
 	lf.m.Lock()
-	defer lf.m.Unlock()
-
 	c, ok := lf.registry[name]
-	if !ok {
-		return c, fmt.Errorf("logger: no log driver named '%s' is registered", name)
+	lf.m.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	// Not a built-in driver, it may be provided by a plugin
+	c, err := lookupPlugin(name)
+	if err != nil {
+		logrus.Debug(err)
+		return nil, fmt.Errorf("logger: no log driver named '%s' is registered", name)
 	}
 	return c, nil
 }
@@ -74,16 +83,77 @@ func RegisterLogOptValidator(name string, l LogOptValidator) error {
 }
 
 // GetLogDriver provides the logging driver builder for a logging driver name.
+// Drivers that aren't built in are looked up as LogDriver plugins.
 func GetLogDriver(name string) (Creator, error) {
 	return factory.get(name)
 }
 
+// builtInLogOpts are the options supported by all the logging drivers.
+// They are not passed to the validators of the drivers.
+var builtInLogOpts = map[string]bool{
+	ModeOpt:          true,
+	MaxBufferSizeOpt: true,
+}
+
+// externalValidators check the built-in options added by other packages.
+var externalValidators []LogOptValidator
+
+// AddBuiltinLogOpts adds options supported by all the logging drivers, for
+// the packages wrapping the drivers. It must only be called on package
+// initialization.
+func AddBuiltinLogOpts(opts map[string]bool) {
+	for k, v := range opts {
+		builtInLogOpts[k] = v
+	}
+}
+
+// RegisterExternalValidator adds a validator of the options added with
+// AddBuiltinLogOpts. It gets the options of every logging driver. It must
+// only be called on package initialization.
+func RegisterExternalValidator(v LogOptValidator) {
+	externalValidators = append(externalValidators, v)
+}
+
 // ValidateLogOpts checks the options for the given log driver. The
-// options supported are specific to the LogDriver implementation.
+// options supported are specific to the LogDriver implementation, except
+// for the built-in options which are supported by all of them.
 func ValidateLogOpts(name string, cfg map[string]string) error {
+	if err := validateModeOpts(cfg); err != nil {
+		return err
+	}
+	for _, v := range externalValidators {
+		if err := v(cfg); err != nil {
+			return err
+		}
+	}
+
 	l := factory.getLogOptValidator(name)
-	if l != nil {
-		return l(cfg)
+	if l == nil {
+		return nil
+	}
+	driverOpts := make(map[string]string, len(cfg))
+	for k, v := range cfg {
+		if !builtInLogOpts[k] {
+			driverOpts[k] = v
+		}
+	}
+	return l(driverOpts)
+}
+
+func validateModeOpts(cfg map[string]string) error {
+	mode := cfg[ModeOpt]
+	switch mode {
+	case "", ModeBlocking, ModeNonBlocking:
+	default:
+		return fmt.Errorf("logger: logging mode not supported: %s", mode)
+	}
+	if s, ok := cfg[MaxBufferSizeOpt]; ok {
+		if mode != ModeNonBlocking {
+			return fmt.Errorf("logger: %s option is only supported with '%s=%s'", MaxBufferSizeOpt, ModeOpt, ModeNonBlocking)
+		}
+		if _, err := units.RAMInBytes(s); err != nil {
+			return fmt.Errorf("logger: error parsing option %s: %v", MaxBufferSizeOpt, err)
+		}
 	}
 	return nil
 }
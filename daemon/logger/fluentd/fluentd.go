@@ -22,7 +22,6 @@ type fluentd struct {
 	containerID   string
 	containerName string
 	writer        *fluent.Fluent
-	extra         map[string]string
 }
 
 const (
@@ -70,8 +69,6 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		return nil, err
 	}
 
-	extra := ctx.ExtraAttributes(nil)
-
 	bufferLimit := defaultBufferLimit
 	if ctx.Config[bufferLimitKey] != "" {
 		bl64, err := units.RAMInBytes(ctx.Config[bufferLimitKey])
@@ -127,7 +124,6 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		containerID:   ctx.ContainerID,
 		containerName: ctx.ContainerName,
 		writer:        log,
-		extra:         extra,
 	}, nil
 }
 
@@ -138,7 +134,7 @@ func (f *fluentd) Log(msg *logger.Message) error {
 		"source":         msg.Source,
 		"log":            string(msg.Line),
 	}
-	for k, v := range f.extra {
+	for k, v := range msg.Attrs {
 		data[k] = v
 	}
 	// fluent-logger-golang buffers logs from failures and disconnections,
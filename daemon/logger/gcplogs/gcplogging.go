@@ -128,7 +128,6 @@ func New(ctx logger.Context) (logger.Logger, error) {
 			ImageName: ctx.ContainerImageName,
 			ImageID:   ctx.ContainerImageID,
 			Created:   ctx.ContainerCreated,
-			Metadata:  ctx.ExtraAttributes(nil),
 		},
 	}
 
@@ -172,11 +171,17 @@ func ValidateLogOpts(cfg map[string]string) error {
 }
 
 func (l *gcplogs) Log(m *logger.Message) error {
+	container := l.container
+	if len(m.Attrs) > 0 {
+		c := *l.container
+		c.Metadata = m.Attrs
+		container = &c
+	}
 	return l.client.Log(logging.Entry{
 		Time: m.Timestamp,
 		Payload: &dockerLogEntry{
 			Instance:  l.instance,
-			Container: l.container,
+			Container: container,
 			Data:      string(m.Line),
 		},
 	})
@@ -12,6 +12,7 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Graylog2/go-gelf/gelf"
@@ -27,6 +28,7 @@ type gelfLogger struct {
 	writer   *gelf.Writer
 	ctx      logger.Context
 	hostname string
+	extra    map[string]interface{}
 	rawExtra json.RawMessage
 }
 
@@ -74,16 +76,6 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		"_created":        ctx.ContainerCreated,
 	}
 
-	extraAttrs := ctx.ExtraAttributes(func(key string) string {
-		if key[0] == '_' {
-			return key
-		}
-		return "_" + key
-	})
-	for k, v := range extraAttrs {
-		extra[k] = v
-	}
-
 	rawExtra, err := json.Marshal(extra)
 	if err != nil {
 		return nil, err
@@ -120,6 +112,7 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		writer:   gelfWriter,
 		ctx:      ctx,
 		hostname: hostname,
+		extra:    extra,
 		rawExtra: rawExtra,
 	}, nil
 }
@@ -130,13 +123,36 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 		level = gelf.LOG_ERR
 	}
 
+	rawExtra := s.rawExtra
+	if len(msg.Attrs) > 0 || msg.Partial {
+		// The attributes are additional fields, prefixed with an underscore,
+		// as well as the mark of the partial messages
+		extra := make(map[string]interface{}, len(s.extra)+len(msg.Attrs))
+		for k, v := range s.extra {
+			extra[k] = v
+		}
+		for k, v := range msg.Attrs {
+			if !strings.HasPrefix(k, "_") {
+				k = "_" + k
+			}
+			extra[k] = v
+		}
+		if msg.Partial {
+			extra["_partial_message"] = true
+		}
+		var err error
+		if rawExtra, err = json.Marshal(extra); err != nil {
+			return err
+		}
+	}
+
 	m := gelf.Message{
 		Version:  "1.1",
 		Host:     s.hostname,
 		Short:    string(msg.Line),
 		TimeUnix: float64(msg.Timestamp.UnixNano()/int64(time.Millisecond)) / 1000.0,
 		Level:    level,
-		RawExtra: s.rawExtra,
+		RawExtra: rawExtra,
 	}
 
 	if err := s.writer.WriteMessage(&m); err != nil {
@@ -15,7 +15,12 @@ import (
 	"github.com/docker/docker/daemon/logger/loggerutils"
 )
 
-const name = "journald"
+const (
+	name = "journald"
+
+	// partialField is set on the journal entries of the partial messages.
+	partialField = "CONTAINER_PARTIAL_MESSAGE"
+)
 
 type journald struct {
 	vars    map[string]string // additional variables and values to send to the journal along with the log message
@@ -61,10 +66,6 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		"CONTAINER_NAME":    name,
 		"CONTAINER_TAG":     tag,
 	}
-	extraAttrs := ctx.ExtraAttributes(strings.ToTitle)
-	for k, v := range extraAttrs {
-		vars[k] = v
-	}
 	return &journald{vars: vars, readers: readerList{readers: make(map[*logger.LogWatcher]*logger.LogWatcher)}}, nil
 }
 
@@ -84,10 +85,41 @@ func validateLogOpt(cfg map[string]string) error {
 }
 
 func (s *journald) Log(msg *logger.Message) error {
+	vars := s.vars
+	if len(msg.Attrs) > 0 || msg.Partial {
+		vars = make(map[string]string, len(s.vars)+len(msg.Attrs)+1)
+		for k, v := range s.vars {
+			vars[k] = v
+		}
+		for k, v := range msg.Attrs {
+			if k = fieldName(k); k != "" {
+				vars[k] = v
+			}
+		}
+		if msg.Partial {
+			vars[partialField] = "true"
+		}
+	}
 	if msg.Source == "stderr" {
-		return journal.Send(string(msg.Line), journal.PriErr, s.vars)
+		return journal.Send(string(msg.Line), journal.PriErr, vars)
 	}
-	return journal.Send(string(msg.Line), journal.PriInfo, s.vars)
+	return journal.Send(string(msg.Line), journal.PriInfo, vars)
+}
+
+// fieldName returns the key as a journal field name, made of upper case
+// letters, digits and underscores, and not starting with an underscore.
+// Labels such as com.example.rack become COM_EXAMPLE_RACK.
+func fieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9', r == '_':
+			return r
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return '_'
+	}, key)
+	return strings.TrimLeft(name, "_")
 }
 
 func (s *journald) Name() string {
@@ -50,6 +50,12 @@ package journald
 //	}
 //	return rc;
 //}
+//static int is_partial(sd_journal *j)
+//{
+//	const void *data;
+//	size_t length;
+//	return sd_journal_get_data(j, "CONTAINER_PARTIAL_MESSAGE", &data, &length) == 0;
+//}
 //static int wait_for_data_or_close(sd_journal *j, int pipefd)
 //{
 //	struct pollfd fds[2];
@@ -115,11 +121,20 @@ func (s *journald) Close() error {
 	return nil
 }
 
-func (s *journald) drainJournal(logWatcher *logger.LogWatcher, config logger.ReadConfig, j *C.sd_journal, oldCursor string) string {
+// drainJournal sends the entries from the current position of the journal
+// forward. It returns the cursor of the last entry read, and whether an entry
+// past config.Until was reached, in which case there is nothing left to send.
+func (s *journald) drainJournal(logWatcher *logger.LogWatcher, config logger.ReadConfig, j *C.sd_journal, oldCursor string) (string, bool) {
 	var msg, cursor *C.char
 	var length C.size_t
 	var stamp C.uint64_t
 	var priority C.int
+	var untilUnixMicro uint64
+	var done bool
+
+	if !config.Until.IsZero() {
+		untilUnixMicro = uint64(config.Until.UnixNano() / 1000)
+	}
 
 	// Walk the journal from here forward until we run out of new entries.
 drain:
@@ -141,9 +156,18 @@ drain:
 			if C.sd_journal_get_realtime_usec(j, &stamp) != 0 {
 				break
 			}
+			// Stop at the end of the requested window.
+			if untilUnixMicro != 0 && uint64(stamp) > untilUnixMicro {
+				done = true
+				break
+			}
 			// Set up the time and text of the entry.
 			timestamp := time.Unix(int64(stamp)/1000000, (int64(stamp)%1000000)*1000)
-			line := append(C.GoBytes(unsafe.Pointer(msg), C.int(length)), "\n"...)
+			line := C.GoBytes(unsafe.Pointer(msg), C.int(length))
+			partial := C.is_partial(j) != 0
+			if !partial {
+				line = append(line, "\n"...)
+			}
 			// Recover the stream name by mapping
 			// from the journal priority back to
 			// the stream that we would have
@@ -158,7 +182,7 @@ drain:
 			}
 			// Send the log message.
 			cid := s.vars["CONTAINER_ID_FULL"]
-			logWatcher.Msg <- &logger.Message{ContainerID: cid, Line: line, Source: source, Timestamp: timestamp}
+			logWatcher.Msg <- &logger.Message{ContainerID: cid, Line: line, Source: source, Timestamp: timestamp, Partial: partial}
 		}
 		// If we're at the end of the journal, we're done (for now).
 		if C.sd_journal_next(j) <= 0 {
@@ -170,7 +194,7 @@ drain:
 		retCursor = C.GoString(cursor)
 		C.free(unsafe.Pointer(cursor))
 	}
-	return retCursor
+	return retCursor, done
 }
 
 func (s *journald) followJournal(logWatcher *logger.LogWatcher, config logger.ReadConfig, j *C.sd_journal, pfd [2]C.int, cursor string) {
@@ -182,7 +206,11 @@ func (s *journald) followJournal(logWatcher *logger.LogWatcher, config logger.Re
 		// or we hit an error.
 		status := C.wait_for_data_or_close(j, pfd[0])
 		for status == 1 {
-			cursor = s.drainJournal(logWatcher, config, j, cursor)
+			var done bool
+			cursor, done = s.drainJournal(logWatcher, config, j, cursor)
+			if done {
+				break
+			}
 			status = C.wait_for_data_or_close(j, pfd[0])
 		}
 		if status < 0 {
@@ -199,19 +227,27 @@ func (s *journald) followJournal(logWatcher *logger.LogWatcher, config logger.Re
 		C.sd_journal_close(j)
 		close(logWatcher.Msg)
 	}()
+	// Stop following at config.Until, even if nothing is logged past it.
+	var untilC <-chan time.Time
+	if !config.Until.IsZero() {
+		untilTimer := time.NewTimer(config.Until.Sub(time.Now()))
+		defer untilTimer.Stop()
+		untilC = untilTimer.C
+	}
 	// Wait until we're told to stop.
 	select {
 	case <-logWatcher.WatchClose():
-		// Notify the other goroutine that its work is done.
-		C.close(pfd[1])
+	case <-untilC:
 	}
+	// Notify the other goroutine that its work is done.
+	C.close(pfd[1])
 }
 
 func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadConfig) {
 	var j *C.sd_journal
 	var cmatch *C.char
 	var stamp C.uint64_t
-	var sinceUnixMicro uint64
+	var sinceUnixMicro, untilUnixMicro uint64
 	var pipes [2]C.int
 	cursor := ""
 
@@ -252,10 +288,20 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 		nano := config.Since.UnixNano()
 		sinceUnixMicro = uint64(nano / 1000)
 	}
+	if !config.Until.IsZero() {
+		nano := config.Until.UnixNano()
+		untilUnixMicro = uint64(nano / 1000)
+	}
 	if config.Tail > 0 {
 		lines := config.Tail
-		// Start at the end of the journal.
-		if C.sd_journal_seek_tail(j) < 0 {
+		if untilUnixMicro != 0 {
+			// Start right after the end of the window.
+			if C.sd_journal_seek_realtime_usec(j, C.uint64_t(untilUnixMicro+1)) < 0 {
+				logWatcher.Err <- fmt.Errorf("error seeking to end time in journal")
+				return
+			}
+		} else if C.sd_journal_seek_tail(j) < 0 {
+			// Start at the end of the journal.
 			logWatcher.Err <- fmt.Errorf("error seeking to end of journal")
 			return
 		}
@@ -301,8 +347,8 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 			return
 		}
 	}
-	cursor = s.drainJournal(logWatcher, config, j, "")
-	if config.Follow {
+	cursor, done := s.drainJournal(logWatcher, config, j, "")
+	if config.Follow && !done {
 		// Allocate a descriptor for following the journal, if we'll
 		// need one.  Do it here so that we can report if it fails.
 		if fd := C.sd_journal_get_fd(j); fd < C.int(0) {
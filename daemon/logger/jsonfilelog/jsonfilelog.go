@@ -26,7 +26,6 @@ type JSONFileLogger struct {
 	writer  *loggerutils.RotateFileWriter
 	mu      sync.Mutex
 	readers map[*logger.LogWatcher]struct{} // stores the active log followers
-	extra   []byte                          // json-encoded extra attributes
 }
 
 func init() {
@@ -60,26 +59,27 @@ func New(ctx logger.Context) (logger.Logger, error) {
 			return nil, fmt.Errorf("max-file cannot be less than 1")
 		}
 	}
-
-	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles)
-	if err != nil {
-		return nil, err
-	}
-
-	var extra []byte
-	if attrs := ctx.ExtraAttributes(nil); len(attrs) > 0 {
+	var compress bool
+	if compressString, ok := ctx.Config["compress"]; ok {
 		var err error
-		extra, err = json.Marshal(attrs)
+		compress, err = strconv.ParseBool(compressString)
 		if err != nil {
 			return nil, err
 		}
+		if compress && (maxFiles < 2 || capval == -1) {
+			return nil, fmt.Errorf("compress cannot be true without max-size and a max-file of at least 2")
+		}
+	}
+
+	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles, compress)
+	if err != nil {
+		return nil, err
 	}
 
 	return &JSONFileLogger{
 		buf:     bytes.NewBuffer(nil),
 		writer:  writer,
 		readers: make(map[*logger.LogWatcher]struct{}),
-		extra:   extra,
 	}, nil
 }
 
@@ -89,12 +89,24 @@ func (l *JSONFileLogger) Log(msg *logger.Message) error {
 	if err != nil {
 		return err
 	}
+	var attrs []byte
+	if len(msg.Attrs) > 0 {
+		attrs, err = json.Marshal(msg.Attrs)
+		if err != nil {
+			return err
+		}
+	}
+	line := msg.Line
+	// The newline is left out of the partial messages, to join them on read
+	if !msg.Partial {
+		line = append(line, '\n')
+	}
 	l.mu.Lock()
 	err = (&jsonlog.JSONLogs{
-		Log:      append(msg.Line, '\n'),
+		Log:      line,
 		Stream:   msg.Source,
 		Created:  timestamp,
-		RawAttrs: l.extra,
+		RawAttrs: attrs,
 	}).MarshalJSONBuf(l.buf)
 	if err != nil {
 		l.mu.Unlock()
@@ -109,12 +121,17 @@ func (l *JSONFileLogger) Log(msg *logger.Message) error {
 	return err
 }
 
-// ValidateLogOpt looks for json specific log options max-file & max-size.
+// ValidateLogOpt looks for json specific log options max-file, max-size
+// & compress.
 func ValidateLogOpt(cfg map[string]string) error {
 	for key := range cfg {
 		switch key {
 		case "max-file":
 		case "max-size":
+		case "compress":
+			if _, err := strconv.ParseBool(cfg[key]); err != nil {
+				return fmt.Errorf("invalid value for log opt 'compress' for json-file log driver: %s", cfg[key])
+			}
 		case "labels":
 		case "env":
 		default:
@@ -152,6 +152,157 @@ func TestJSONFileLoggerWithOpts(t *testing.T) {
 
 }
 
+func TestJSONFileLoggerCompress(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	ctx := logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      map[string]string{"max-file": "3", "max-size": "1k", "compress": "true"},
+	}
+	l, err := New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 40; i++ {
+		if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line" + strconv.Itoa(i)), Source: "src1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{filename + ".1.gz", filename + ".2.gz"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatalf("expected the rotated file to be compressed: %v", err)
+		}
+	}
+	if _, err := os.Stat(filename + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("expected the uncompressed rotated file to be removed, got %v", err)
+	}
+
+	l, err = New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	defer watcher.Close()
+	var i int
+	for msg := range watcher.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("expected %q, got %q", expected, msg.Line)
+		}
+		i++
+	}
+	if i != 40 {
+		t.Fatalf("expected 40 lines read across the compressed files, got %d", i)
+	}
+}
+
+func TestJSONFileLoggerReadUntil(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filepath.Join(tmp, "container.log"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	start := time.Unix(1460000000, 0)
+	for i := 0; i < 10; i++ {
+		msg := &logger.Message{ContainerID: cid, Line: []byte("line" + strconv.Itoa(i)), Source: "src1", Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, c := range []struct {
+		config   logger.ReadConfig
+		expected []string
+	}{
+		{
+			config:   logger.ReadConfig{Tail: -1, Until: start.Add(2 * time.Second)},
+			expected: []string{"line0\n", "line1\n", "line2\n"},
+		},
+		{
+			config:   logger.ReadConfig{Tail: -1, Since: start.Add(4 * time.Second), Until: start.Add(5 * time.Second)},
+			expected: []string{"line4\n", "line5\n"},
+		},
+		{
+			config:   logger.ReadConfig{Tail: 2, Until: start.Add(6 * time.Second)},
+			expected: []string{"line5\n", "line6\n"},
+		},
+	} {
+		watcher := l.(logger.LogReader).ReadLogs(c.config)
+		var lines []string
+		for msg := range watcher.Msg {
+			lines = append(lines, string(msg.Line))
+		}
+		watcher.Close()
+		if !reflect.DeepEqual(lines, c.expected) {
+			t.Fatalf("expected %q reading with %+v, got %q", c.expected, c.config, lines)
+		}
+	}
+}
+
+func TestJSONFileLoggerPartial(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Context{
+		ContainerID: "container",
+		LogPath:     filename,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := l.Log(&logger.Message{Line: []byte("part1"), Source: "src1", Partial: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log(&logger.Message{Line: []byte("part2"), Source: "src1"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"log":"part1","stream":"src1","time":"0001-01-01T00:00:00Z"}
+{"log":"part2\n","stream":"src1","time":"0001-01-01T00:00:00Z"}
+`
+	if string(res) != expected {
+		t.Fatalf("Wrong log content: %q, expected %q", res, expected)
+	}
+
+	watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	defer watcher.Close()
+	var msgs []*logger.Message
+	for msg := range watcher.Msg {
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) != 2 || !msgs[0].Partial || msgs[1].Partial || string(msgs[0].Line)+string(msgs[1].Line) != "part1part2\n" {
+		t.Fatalf("expected the partial message to be read back, got %+v", msgs)
+	}
+}
+
 func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
 	tmp, err := ioutil.TempDir("", "docker-logger-")
@@ -161,18 +312,19 @@ func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 	defer os.RemoveAll(tmp)
 	filename := filepath.Join(tmp, "container.log")
 	config := map[string]string{"labels": "rack,dc", "env": "environ,debug,ssl"}
-	l, err := New(logger.Context{
+	ctx := logger.Context{
 		ContainerID:     cid,
 		LogPath:         filename,
 		Config:          config,
 		ContainerLabels: map[string]string{"rack": "101", "dc": "lhr"},
 		ContainerEnv:    []string{"environ=production", "debug=false", "port=10001", "ssl=true"},
-	})
+	}
+	l, err := New(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer l.Close()
-	if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line"), Source: "src1"}); err != nil {
+	if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line"), Source: "src1", Attrs: ctx.LogAttributes()}); err != nil {
 		t.Fatal(err)
 	}
 	res, err := ioutil.ReadFile(filename)
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
 	"github.com/docker/docker/pkg/filenotify"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/jsonlog"
@@ -27,6 +29,7 @@ func decodeLogLine(dec *json.Decoder, l *jsonlog.JSONLog) (*logger.Message, erro
 		Source:    l.Stream,
 		Timestamp: l.Created,
 		Line:      []byte(l.Log),
+		Partial:   !strings.HasSuffix(l.Log, "\n"),
 	}
 	return msg, nil
 }
@@ -46,7 +49,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	pth := l.writer.LogPath()
 	var files []io.ReadSeeker
 	for i := l.writer.MaxFiles(); i > 1; i-- {
-		f, err := os.Open(fmt.Sprintf("%s.%d", pth, i-1))
+		f, err := loggerutils.OpenRotatedFile(fmt.Sprintf("%s.%d", pth, i-1))
 		if err != nil {
 			if !os.IsNotExist(err) {
 				logWatcher.Err <- err
@@ -65,7 +68,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 
 	if config.Tail != 0 {
 		tailer := ioutils.MultiReadSeeker(append(files, latestFile)...)
-		tailFile(tailer, logWatcher, config.Tail, config.Since)
+		tailFile(tailer, logWatcher, config.Tail, config.Since, config.Until)
 	}
 
 	// close all the rotated files
@@ -88,7 +91,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.mu.Unlock()
 
 	notifyRotate := l.writer.NotifyRotate()
-	followLogs(latestFile, logWatcher, notifyRotate, config.Since)
+	followLogs(latestFile, logWatcher, notifyRotate, config.Since, config.Until)
 
 	l.mu.Lock()
 	delete(l.readers, logWatcher)
@@ -97,9 +100,12 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.writer.NotifyRotateEvict(notifyRotate)
 }
 
-func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since time.Time) {
+func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since, until time.Time) {
 	var rdr io.Reader = f
-	if tail > 0 {
+	// The last lines of the file may be past until, the tail of the window
+	// is kept while reading it instead.
+	var window []*logger.Message
+	if tail > 0 && until.IsZero() {
 		ls, err := tailfile.TailFile(f, tail)
 		if err != nil {
 			logWatcher.Err <- err
@@ -114,17 +120,31 @@ func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since ti
 		if err != nil {
 			if err != io.EOF {
 				logWatcher.Err <- err
+				return
 			}
-			return
+			break
 		}
 		if !since.IsZero() && msg.Timestamp.Before(since) {
 			continue
 		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			break
+		}
+		if tail > 0 && !until.IsZero() {
+			window = append(window, msg)
+			if len(window) > tail {
+				window = window[1:]
+			}
+			continue
+		}
+		logWatcher.Msg <- msg
+	}
+	for _, msg := range window {
 		logWatcher.Msg <- msg
 	}
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since time.Time) {
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since, until time.Time) {
 	dec := json.NewDecoder(f)
 	l := &jsonlog.JSONLog{}
 
@@ -150,6 +170,14 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		}
 	}
 
+	// Stop following at until, even if nothing is logged past it.
+	var untilC <-chan time.Time
+	if !until.IsZero() {
+		untilTimer := time.NewTimer(until.Sub(time.Now()))
+		defer untilTimer.Stop()
+		untilC = untilTimer.C
+	}
+
 	var retries int
 	for {
 		msg, err := decodeLogLine(dec, l)
@@ -186,6 +214,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 			case <-logWatcher.WatchClose():
 				fileWatcher.Remove(name)
 				return
+			case <-untilC:
+				fileWatcher.Remove(name)
+				return
 			case <-notifyRotate:
 				f.Close()
 				fileWatcher.Remove(name)
@@ -216,6 +247,10 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		if !since.IsZero() && msg.Timestamp.Before(since) {
 			continue
 		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			fileWatcher.Remove(name)
+			return
+		}
 		select {
 		case logWatcher.Msg <- msg:
 		case <-logWatcher.WatchClose():
@@ -228,6 +263,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 				if !since.IsZero() && msg.Timestamp.Before(since) {
 					continue
 				}
+				if !until.IsZero() && msg.Timestamp.After(until) {
+					return
+				}
 				logWatcher.Msg <- msg
 			}
 		}
@@ -0,0 +1,252 @@
+// Package local provides a Logger implementation storing the logs in a
+// compact binary format, faster to read back than the json-file logs.
+//
+// The messages are stored as records framed by their size, so that the
+// logs can be read both forward and backward:
+//
+//	size uint32 | timestamp int64 | source length uint8 | source | line | size uint32
+//
+// where size is the length of the fields between the two size fields, and
+// the timestamp is in nanoseconds since the epoch. The high bit of the
+// source length is set on the partial messages. Each log file has an
+// index next to it, a list of timestamp int64 | offset int64 entries
+// pointing to a record at least every indexInterval bytes, to find where to
+// start reading from a given time without scanning the file.
+package local
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/go-units"
+)
+
+const (
+	// Name is the name of the local logging driver.
+	Name = "local"
+
+	defaultMaxSize = "20m"
+	defaultMaxFile = 5
+
+	// indexSuffix is appended to the name of a log file for its index.
+	indexSuffix = ".idx"
+	// indexInterval is the number of bytes of records between two index
+	// entries.
+	indexInterval = 32 * 1024
+
+	sizeLen       = 4
+	timestampLen  = 8
+	indexEntryLen = 16
+	maxSourceLen  = 127
+	partialFlag   = 0x80
+)
+
+func init() {
+	if err := logger.RegisterLogDriver(Name, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(Name, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+type driver struct {
+	mu      sync.Mutex
+	writer  *loggerutils.RotateFileWriter
+	index   *os.File
+	offset  int64 // offset of the next record in the current file
+	indexed int64 // offset of the last indexed record, -1 if none
+	buf     []byte
+	readers map[*logger.LogWatcher]struct{} // stores the active log followers
+}
+
+// New creates a local logger writing to the LogPath of the context. The
+// logs are rotated and the rotated files compressed by default.
+func New(ctx logger.Context) (logger.Logger, error) {
+	capval, err := units.FromHumanSize(defaultMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	if capacity, ok := ctx.Config["max-size"]; ok {
+		capval, err = units.FromHumanSize(capacity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	maxFiles := defaultMaxFile
+	if maxFileString, ok := ctx.Config["max-file"]; ok {
+		maxFiles, err = strconv.Atoi(maxFileString)
+		if err != nil {
+			return nil, err
+		}
+		if maxFiles < 1 {
+			return nil, fmt.Errorf("max-file cannot be less than 1")
+		}
+	}
+	compress := maxFiles > 1
+	if compressString, ok := ctx.Config["compress"]; ok {
+		compress, err = strconv.ParseBool(compressString)
+		if err != nil {
+			return nil, err
+		}
+		if compress && maxFiles < 2 {
+			return nil, fmt.Errorf("compress cannot be true with a max-file of less than 2")
+		}
+	}
+
+	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles, compress)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(ctx.LogPath)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+	index, err := os.OpenFile(ctx.LogPath+indexSuffix, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	l := &driver{
+		writer:  writer,
+		index:   index,
+		offset:  fi.Size(),
+		indexed: -1,
+		readers: make(map[*logger.LogWatcher]struct{}),
+	}
+	writer.SetRotateHook(l.rotateIndex)
+	return l, nil
+}
+
+// Log writes the message as a record, and indexes it if the last indexed
+// record is far enough.
+func (l *driver) Log(msg *logger.Message) error {
+	if len(msg.Source) > maxSourceLen {
+		return fmt.Errorf("source of the message too long: %s", msg.Source)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf = encodeRecord(l.buf[:0], msg)
+	// The offset is reset if the file is rotated before the record is written
+	n, err := l.writer.Write(l.buf)
+	if err != nil {
+		return err
+	}
+	offset := l.offset
+	l.offset += int64(n)
+
+	if l.indexed >= 0 && offset-l.indexed < indexInterval {
+		return nil
+	}
+	var entry [indexEntryLen]byte
+	binary.BigEndian.PutUint64(entry[:8], uint64(msg.Timestamp.UnixNano()))
+	binary.BigEndian.PutUint64(entry[8:], uint64(offset))
+	if _, err := l.index.Write(entry[:]); err != nil {
+		return err
+	}
+	l.indexed = offset
+	return nil
+}
+
+// rotateIndex rotates the index along with the log file. It's called by
+// the writer, while the driver is locked by Log.
+func (l *driver) rotateIndex() error {
+	if err := l.index.Close(); err != nil {
+		return err
+	}
+	pth := l.writer.LogPath()
+	if maxFiles := l.writer.MaxFiles(); maxFiles > 1 {
+		for i := maxFiles - 1; i > 1; i-- {
+			toPath := pth + "." + strconv.Itoa(i) + indexSuffix
+			fromPath := pth + "." + strconv.Itoa(i-1) + indexSuffix
+			if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(pth+indexSuffix, pth+".1"+indexSuffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	index, err := os.OpenFile(pth+indexSuffix, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	l.index = index
+	l.offset = 0
+	l.indexed = -1
+	return nil
+}
+
+// Name returns the name of this logger.
+func (l *driver) Name() string {
+	return Name
+}
+
+// Close closes the log files and signals all the readers to stop.
+func (l *driver) Close() error {
+	l.mu.Lock()
+	err := l.writer.Close()
+	if ierr := l.index.Close(); err == nil {
+		err = ierr
+	}
+	for r := range l.readers {
+		r.Close()
+		delete(l.readers, r)
+	}
+	l.mu.Unlock()
+	return err
+}
+
+// ValidateLogOpt looks for the local specific log options max-file,
+// max-size and compress.
+func ValidateLogOpt(cfg map[string]string) error {
+	for key, value := range cfg {
+		switch key {
+		case "max-file":
+			if n, err := strconv.Atoi(value); err != nil || n < 1 {
+				return fmt.Errorf("invalid value for log opt 'max-file' for local log driver: %s", value)
+			}
+		case "max-size":
+			if _, err := units.FromHumanSize(value); err != nil {
+				return fmt.Errorf("invalid value for log opt 'max-size' for local log driver: %s", value)
+			}
+		case "compress":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid value for log opt 'compress' for local log driver: %s", value)
+			}
+		default:
+			return fmt.Errorf("unknown log opt '%s' for local log driver", key)
+		}
+	}
+	return nil
+}
+
+// encodeRecord appends the record of the message to buf.
+func encodeRecord(buf []byte, msg *logger.Message) []byte {
+	size := timestampLen + 1 + len(msg.Source) + len(msg.Line)
+	var b [timestampLen]byte
+
+	binary.BigEndian.PutUint32(b[:sizeLen], uint32(size))
+	buf = append(buf, b[:sizeLen]...)
+	binary.BigEndian.PutUint64(b[:], uint64(msg.Timestamp.UnixNano()))
+	buf = append(buf, b[:]...)
+	flags := byte(0)
+	if msg.Partial {
+		flags = partialFlag
+	}
+	buf = append(buf, byte(len(msg.Source))|flags)
+	buf = append(buf, msg.Source...)
+	buf = append(buf, msg.Line...)
+	binary.BigEndian.PutUint32(b[:sizeLen], uint32(size))
+	return append(buf, b[:sizeLen]...)
+}
@@ -0,0 +1,208 @@
+package local
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+func expectedLines(from, to int) []string {
+	var lines []string
+	for i := from; i <= to; i++ {
+		lines = append(lines, fmt.Sprintf("line%d\n", i))
+	}
+	return lines
+}
+
+func readLines(t *testing.T, l logger.Logger, config logger.ReadConfig) []string {
+	watcher := l.(logger.LogReader).ReadLogs(config)
+	defer watcher.Close()
+	var lines []string
+	for {
+		select {
+		case msg, ok := <-watcher.Msg:
+			if !ok {
+				return lines
+			}
+			lines = append(lines, string(msg.Line))
+		case err := <-watcher.Err:
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLocalReadLogs(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	ctx := logger.Context{
+		ContainerID: "container",
+		LogPath:     filepath.Join(tmp, "container.log"),
+		Config:      map[string]string{"max-size": "1k", "max-file": "3"},
+	}
+	l, err := New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(1460000000, 0)
+	for i := 0; i < 150; i++ {
+		msg := &logger.Message{Line: []byte(fmt.Sprintf("line%d", i)), Source: "stdout", Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ctx.LogPath + ".1.gz"); err != nil {
+		t.Fatalf("expected the rotated file to be compressed by default: %v", err)
+	}
+
+	l, err = New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	all := readLines(t, l, logger.ReadConfig{Tail: -1})
+	var first int
+	if len(all) == 0 {
+		t.Fatal("expected logs to be read")
+	}
+	if _, err := fmt.Sscanf(all[0], "line%d\n", &first); err != nil {
+		t.Fatal(err)
+	}
+	if first == 0 || first > 100 {
+		t.Fatalf("expected the oldest logs only to be rotated away, the first line is %q", all[0])
+	}
+	if expected := expectedLines(first, 149); !reflect.DeepEqual(all, expected) {
+		t.Fatalf("expected %q, got %q", expected, all)
+	}
+
+	for _, c := range []struct {
+		config   logger.ReadConfig
+		expected []string
+	}{
+		{
+			config:   logger.ReadConfig{Tail: -1, Since: start.Add(120 * time.Second)},
+			expected: expectedLines(120, 149),
+		},
+		{
+			config:   logger.ReadConfig{Tail: 5},
+			expected: expectedLines(145, 149),
+		},
+		{
+			config:   logger.ReadConfig{Tail: 50},
+			expected: expectedLines(100, 149),
+		},
+		{
+			config:   logger.ReadConfig{Tail: 5, Until: start.Add(130 * time.Second)},
+			expected: expectedLines(126, 130),
+		},
+		{
+			config:   logger.ReadConfig{Tail: 50, Since: start.Add(140 * time.Second)},
+			expected: expectedLines(140, 149),
+		},
+		{
+			config:   logger.ReadConfig{Tail: -1, Since: start.Add(125 * time.Second), Until: start.Add(135 * time.Second)},
+			expected: expectedLines(125, 135),
+		},
+	} {
+		if lines := readLines(t, l, c.config); !reflect.DeepEqual(lines, c.expected) {
+			t.Fatalf("expected %q reading with %+v, got %q", c.expected, c.config, lines)
+		}
+	}
+}
+
+func TestLocalFollowLogs(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	l, err := New(logger.Context{
+		ContainerID: "container",
+		LogPath:     filepath.Join(tmp, "container.log"),
+		Config:      map[string]string{"max-size": "1k", "max-file": "2", "compress": "false"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1, Follow: true})
+	defer watcher.Close()
+
+	// The logs are followed across the rotations of the file
+	for i := 0; i < 100; i++ {
+		if err := l.Log(&logger.Message{Line: []byte(fmt.Sprintf("line%d", i)), Source: "stdout", Timestamp: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case msg := <-watcher.Msg:
+			if expected := fmt.Sprintf("line%d\n", i); string(msg.Line) != expected {
+				t.Fatalf("expected %q, got %q", expected, msg.Line)
+			}
+		case err := <-watcher.Err:
+			t.Fatal(err)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout following line%d", i)
+		}
+	}
+}
+
+func TestLocalPartialMessages(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	l, err := New(logger.Context{
+		ContainerID: "container",
+		LogPath:     filepath.Join(tmp, "container.log"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, msg := range []*logger.Message{
+		{Line: []byte("part1"), Source: "stdout", Timestamp: time.Now(), Partial: true},
+		{Line: []byte("part2"), Source: "stdout", Timestamp: time.Now()},
+	} {
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lines := readLines(t, l, logger.ReadConfig{Tail: -1}); !reflect.DeepEqual(lines, []string{"part1", "part2\n"}) {
+		t.Fatalf("expected the partial message without a newline, got %q", lines)
+	}
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	for _, opts := range []map[string]string{
+		{"max-file": "0"},
+		{"max-size": "big"},
+		{"compress": "maybe"},
+		{"labels": "foo"},
+	} {
+		if err := ValidateLogOpt(opts); err == nil {
+			t.Fatalf("expected an error validating %v", opts)
+		}
+	}
+	if err := ValidateLogOpt(map[string]string{"max-file": "3", "max-size": "10m", "compress": "false"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(logger.Context{Config: map[string]string{"max-file": "1", "compress": "true"}}); err == nil {
+		t.Fatal("expected an error compressing without rotated files")
+	}
+}
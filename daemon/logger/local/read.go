@@ -0,0 +1,464 @@
+package local
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/docker/pkg/filenotify"
+)
+
+var errCorrupted = errors.New("corrupted log record")
+
+// logFile is one of the log files, with its index. The file is opened the
+// first time it's needed, the rotated files may have to be decompressed.
+type logFile struct {
+	path  string
+	index []indexEntry
+	f     loggerutils.ReadSeekCloser
+}
+
+type indexEntry struct {
+	timestamp int64
+	offset    int64
+}
+
+func (lf *logFile) open() (loggerutils.ReadSeekCloser, error) {
+	if lf.f == nil {
+		f, err := loggerutils.OpenRotatedFile(lf.path)
+		if err != nil {
+			return nil, err
+		}
+		lf.f = f
+	}
+	return lf.f, nil
+}
+
+// position is the position of a record in the log files.
+type position struct {
+	file   int
+	offset int64
+}
+
+func (p position) before(o position) bool {
+	return p.file < o.file || p.file == o.file && p.offset < o.offset
+}
+
+// ReadLogs implements the logger's LogReader interface for the logs
+// created by this driver.
+func (l *driver) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	logWatcher := logger.NewLogWatcher()
+
+	go l.readLogs(logWatcher, config)
+	return logWatcher
+}
+
+func (l *driver) readLogs(logWatcher *logger.LogWatcher, config logger.ReadConfig) {
+	defer close(logWatcher.Msg)
+
+	// Subscribe first, so that the file followed can't be rotated unnoticed
+	var notifyRotate chan interface{}
+	if config.Follow {
+		notifyRotate = l.writer.NotifyRotate()
+		defer l.writer.NotifyRotateEvict(notifyRotate)
+	}
+
+	pth := l.writer.LogPath()
+	latest, err := os.Open(pth)
+	if err != nil {
+		logWatcher.Err <- err
+		return
+	}
+	files, err := listLogFiles(pth, l.writer.MaxFiles())
+	if err != nil {
+		latest.Close()
+		logWatcher.Err <- err
+		return
+	}
+	last := len(files) - 1
+	files[last].f = latest
+	defer func() {
+		for _, lf := range files[:last] {
+			if lf.f != nil {
+				lf.f.Close()
+			}
+		}
+	}()
+
+	start, err := startPosition(files, config)
+	if err != nil {
+		latest.Close()
+		logWatcher.Err <- err
+		return
+	}
+
+	var r *recordReader
+	for i := start.file; i < len(files); i++ {
+		f, err := files[i].open()
+		if err != nil {
+			if os.IsNotExist(err) {
+				// rotated away since it was listed
+				continue
+			}
+			latest.Close()
+			logWatcher.Err <- err
+			return
+		}
+		var offset int64
+		if i == start.file {
+			offset = start.offset
+		}
+		r, err = newRecordReader(f, offset)
+		if err != nil {
+			latest.Close()
+			logWatcher.Err <- err
+			return
+		}
+		if done := sendRecords(r, logWatcher, config.Since, config.Until); done {
+			latest.Close()
+			return
+		}
+	}
+
+	if !config.Follow {
+		latest.Close()
+		return
+	}
+
+	l.mu.Lock()
+	l.readers[logWatcher] = struct{}{}
+	l.mu.Unlock()
+
+	followLogs(r, latest, logWatcher, notifyRotate, config.Since, config.Until)
+
+	l.mu.Lock()
+	delete(l.readers, logWatcher)
+	l.mu.Unlock()
+}
+
+// listLogFiles returns the rotated log files from the oldest, followed by
+// the current one.
+func listLogFiles(pth string, maxFiles int) ([]*logFile, error) {
+	var files []*logFile
+	for i := maxFiles - 1; i > 0; i-- {
+		rotated := fmt.Sprintf("%s.%d", pth, i)
+		if !exists(rotated) && !exists(rotated+loggerutils.CompressedFileSuffix) {
+			continue
+		}
+		index, err := readIndex(rotated + indexSuffix)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &logFile{path: rotated, index: index})
+	}
+
+	index, err := readIndex(pth + indexSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, &logFile{path: pth, index: index}), nil
+}
+
+func exists(pth string) bool {
+	_, err := os.Stat(pth)
+	return err == nil
+}
+
+// readIndex reads the index at pth. A missing index is empty, the records
+// of the file are then all read.
+func readIndex(pth string) ([]indexEntry, error) {
+	b, err := ioutil.ReadFile(pth)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	index := make([]indexEntry, 0, len(b)/indexEntryLen)
+	for ; len(b) >= indexEntryLen; b = b[indexEntryLen:] {
+		index = append(index, indexEntry{
+			timestamp: int64(binary.BigEndian.Uint64(b[:8])),
+			offset:    int64(binary.BigEndian.Uint64(b[8:indexEntryLen])),
+		})
+	}
+	return index, nil
+}
+
+// startPosition returns the position of the first record to read. The
+// records before it are either before since or not part of the tail.
+func startPosition(files []*logFile, config logger.ReadConfig) (position, error) {
+	last := len(files) - 1
+	if config.Tail == 0 {
+		end, err := files[last].f.Seek(0, os.SEEK_END)
+		return position{last, end}, err
+	}
+
+	var start position
+	if !config.Since.IsZero() {
+		start = sinceStart(files, config.Since)
+	}
+	if config.Tail > 0 {
+		tail, err := tailStart(files, config.Tail, config.Until)
+		if err != nil {
+			return position{}, err
+		}
+		if start.before(tail) {
+			start = tail
+		}
+	}
+	return start, nil
+}
+
+// sinceStart looks up the indexes for the position of the last indexed
+// record logged before since, the records before it are logged before
+// since too.
+func sinceStart(files []*logFile, since time.Time) position {
+	nano := since.UnixNano()
+	for i := len(files) - 1; i >= 0; i-- {
+		index := files[i].index
+		if len(index) == 0 || index[0].timestamp >= nano {
+			continue
+		}
+		n := sort.Search(len(index), func(j int) bool { return index[j].timestamp >= nano })
+		return position{i, index[n-1].offset}
+	}
+	return position{}
+}
+
+// tailStart returns the position of the tail-th last record logged before
+// until, walking the records backward from the end of the files. The
+// indexes tell where the records past until start.
+func tailStart(files []*logFile, tail int, until time.Time) (position, error) {
+	for i := len(files) - 1; i >= 0; i-- {
+		lf := files[i]
+		end := int64(-1)
+		if !until.IsZero() {
+			nano := until.UnixNano()
+			if len(lf.index) > 0 && lf.index[0].timestamp > nano {
+				continue
+			}
+			n := sort.Search(len(lf.index), func(j int) bool { return lf.index[j].timestamp > nano })
+			if n < len(lf.index) {
+				end = lf.index[n].offset
+			}
+		}
+
+		f, err := lf.open()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return position{}, err
+		}
+		if end < 0 {
+			if end, err = f.Seek(0, os.SEEK_END); err != nil {
+				return position{}, err
+			}
+		}
+
+		var b [timestampLen]byte
+		for pos := end; pos > 0; {
+			if _, err := f.Seek(pos-sizeLen, os.SEEK_SET); err != nil {
+				return position{}, err
+			}
+			if _, err := io.ReadFull(f, b[:sizeLen]); err != nil {
+				return position{}, err
+			}
+			start := pos - int64(2*sizeLen+binary.BigEndian.Uint32(b[:sizeLen]))
+			if start < 0 {
+				return position{}, errCorrupted
+			}
+			pos = start
+
+			if !until.IsZero() {
+				if _, err := f.Seek(start+sizeLen, os.SEEK_SET); err != nil {
+					return position{}, err
+				}
+				if _, err := io.ReadFull(f, b[:]); err != nil {
+					return position{}, err
+				}
+				if int64(binary.BigEndian.Uint64(b[:])) > until.UnixNano() {
+					continue
+				}
+			}
+			if tail--; tail == 0 {
+				return position{i, start}, nil
+			}
+		}
+	}
+	return position{}, nil
+}
+
+// sendRecords sends the records up to the end of the file. It returns true
+// once a record past until is read, or the watcher is closed.
+func sendRecords(r *recordReader, logWatcher *logger.LogWatcher, since, until time.Time) bool {
+	for {
+		msg, err := r.next()
+		if err != nil {
+			if err != io.EOF {
+				logWatcher.Err <- err
+				return true
+			}
+			return false
+		}
+		if !since.IsZero() && msg.Timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			return true
+		}
+		select {
+		case logWatcher.Msg <- msg:
+		case <-logWatcher.WatchClose():
+			return true
+		}
+	}
+}
+
+func followLogs(r *recordReader, f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since, until time.Time) {
+	fileWatcher, err := filenotify.New()
+	if err != nil {
+		logWatcher.Err <- err
+		f.Close()
+		return
+	}
+	defer func() {
+		f.Close()
+		fileWatcher.Close()
+	}()
+	name := f.Name()
+
+	if err := fileWatcher.Add(name); err != nil {
+		logrus.WithField("logger", Name).Warnf("falling back to file poller due to error: %v", err)
+		fileWatcher.Close()
+		fileWatcher = filenotify.NewPollingWatcher()
+
+		if err := fileWatcher.Add(name); err != nil {
+			logrus.Debugf("error watching log file for modifications: %v", err)
+			logWatcher.Err <- err
+			return
+		}
+	}
+
+	// Stop following at until, even if nothing is logged past it.
+	var untilC <-chan time.Time
+	if !until.IsZero() {
+		untilTimer := time.NewTimer(until.Sub(time.Now()))
+		defer untilTimer.Stop()
+		untilC = untilTimer.C
+	}
+
+	for {
+		if done := sendRecords(r, logWatcher, since, until); done {
+			return
+		}
+
+		select {
+		case <-fileWatcher.Events():
+		case err := <-fileWatcher.Errors():
+			logWatcher.Err <- err
+			return
+		case <-logWatcher.WatchClose():
+			return
+		case <-untilC:
+			return
+		case <-notifyRotate:
+			// The records left in the rotated file are sent first
+			if done := sendRecords(r, logWatcher, since, until); done {
+				return
+			}
+			f.Close()
+			fileWatcher.Remove(name)
+
+			if f, err = os.Open(name); err != nil {
+				logWatcher.Err <- err
+				return
+			}
+			if err := fileWatcher.Add(name); err != nil {
+				logWatcher.Err <- err
+				return
+			}
+			if r, err = newRecordReader(f, 0); err != nil {
+				logWatcher.Err <- err
+				return
+			}
+		}
+	}
+}
+
+// recordReader reads the records of a log file forward.
+type recordReader struct {
+	f   io.ReadSeeker
+	rd  *bufio.Reader
+	pos int64
+}
+
+func newRecordReader(f io.ReadSeeker, pos int64) (*recordReader, error) {
+	if _, err := f.Seek(pos, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+	return &recordReader{f: f, rd: bufio.NewReader(f), pos: pos}, nil
+}
+
+// next reads the next record. It returns io.EOF at the end of the file,
+// including when the last record is still being written, in which case
+// the record is read from its start on the next call.
+func (r *recordReader) next() (*logger.Message, error) {
+	var b [sizeLen]byte
+	if _, err := io.ReadFull(r.rd, b[:]); err != nil {
+		return nil, r.rewind(err)
+	}
+	size := int(binary.BigEndian.Uint32(b[:]))
+	if size < timestampLen+1 {
+		return nil, errCorrupted
+	}
+	buf := make([]byte, size+sizeLen)
+	if _, err := io.ReadFull(r.rd, buf); err != nil {
+		return nil, r.rewind(err)
+	}
+	if int(binary.BigEndian.Uint32(buf[size:])) != size {
+		return nil, errCorrupted
+	}
+	r.pos += int64(2*sizeLen + size)
+	return decodeRecord(buf[:size])
+}
+
+func (r *recordReader) rewind(err error) error {
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	if _, serr := r.f.Seek(r.pos, os.SEEK_SET); serr != nil {
+		return serr
+	}
+	r.rd.Reset(r.f)
+	return err
+}
+
+// decodeRecord decodes the fields of a record between its size fields.
+func decodeRecord(b []byte) (*logger.Message, error) {
+	ts := int64(binary.BigEndian.Uint64(b[:timestampLen]))
+	b = b[timestampLen:]
+	sourceLen := int(b[0] &^ partialFlag)
+	if len(b) < 1+sourceLen {
+		return nil, errCorrupted
+	}
+	msg := &logger.Message{
+		Source:    string(b[1 : 1+sourceLen]),
+		Timestamp: time.Unix(0, ts).UTC(),
+		Line:      b[1+sourceLen:],
+		Partial:   b[0]&partialFlag != 0,
+	}
+	if !msg.Partial {
+		msg.Line = append(msg.Line, '\n')
+	}
+	return msg, nil
+}
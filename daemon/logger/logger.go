@@ -29,8 +29,21 @@ type Message struct {
 	Line        []byte
 	Source      string
 	Timestamp   time.Time
+	Attrs       LogAttributes
+
+	// Partial is set on the messages holding a part of a line longer than
+	// MaxLineSize, all the parts but the last one. The line of a partial
+	// message has no trailing newline when it's read back, so that the
+	// parts are joined when written one after the other.
+	Partial bool
 }
 
+// LogAttributes are the attributes of the container added to its log
+// messages, the labels and environment variables selected with the labels
+// and env logging options. The same attributes are shared by all the
+// messages of a container, they must not be modified.
+type LogAttributes map[string]string
+
 // Logger is the interface for docker logging drivers.
 type Logger interface {
 	Log(*Message) error
@@ -41,6 +54,7 @@ type Logger interface {
 // ReadConfig is the configuration passed into ReadLogs.
 type ReadConfig struct {
 	Since  time.Time
+	Until  time.Time
 	Tail   int
 	Follow bool
 }
@@ -0,0 +1,121 @@
+// Package cache keeps a local copy of the logs of the containers whose
+// logging driver can't read them back, so that `docker logs` works with
+// any logging driver.
+package cache
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/go-units"
+)
+
+const (
+	// DisabledOpt is the log option disabling the local cache.
+	DisabledOpt = "cache-disabled"
+	// MaxSizeOpt is the log option setting the max size of a cache file.
+	MaxSizeOpt = "cache-max-size"
+	// MaxFileOpt is the log option setting the max number of cache files.
+	MaxFileOpt = "cache-max-file"
+
+	defaultMaxSize = "20m"
+	defaultMaxFile = "5"
+)
+
+func init() {
+	logger.AddBuiltinLogOpts(map[string]bool{
+		DisabledOpt: true,
+		MaxSizeOpt:  true,
+		MaxFileOpt:  true,
+	})
+	logger.RegisterExternalValidator(validateLogCacheOpts)
+}
+
+// WithLocalCache wraps the logging driver so that the messages are also
+// written to a rotated json-file log at logPath, which the logs are read
+// back from. Drivers that read the logs back themselves, or for which the
+// cache is disabled, are returned as is.
+func WithLocalCache(l logger.Logger, ctx logger.Context, logPath string) (logger.Logger, error) {
+	if _, ok := l.(logger.LogReader); ok {
+		return l, nil
+	}
+	if disabled, _ := strconv.ParseBool(ctx.Config[DisabledOpt]); disabled {
+		return l, nil
+	}
+
+	cacheConfig := map[string]string{
+		"max-size": defaultMaxSize,
+		"max-file": defaultMaxFile,
+	}
+	if s, ok := ctx.Config[MaxSizeOpt]; ok {
+		cacheConfig["max-size"] = s
+	}
+	if s, ok := ctx.Config[MaxFileOpt]; ok {
+		cacheConfig["max-file"] = s
+	}
+	cacheCtx := ctx
+	cacheCtx.Config = cacheConfig
+	cacheCtx.LogPath = logPath
+
+	c, err := jsonfilelog.New(cacheCtx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the local cache of the logs: %v", err)
+	}
+	return &loggerWithCache{
+		l:     l,
+		cache: c.(*jsonfilelog.JSONFileLogger),
+	}, nil
+}
+
+// loggerWithCache writes the messages both to the logging driver and to the
+// local cache.
+type loggerWithCache struct {
+	l     logger.Logger
+	cache *jsonfilelog.JSONFileLogger
+}
+
+func (l *loggerWithCache) Log(msg *logger.Message) error {
+	if err := l.cache.Log(msg); err != nil {
+		logrus.Debugf("failed to write a message to the local cache of the %s logging driver: %v", l.l.Name(), err)
+	}
+	return l.l.Log(msg)
+}
+
+func (l *loggerWithCache) Name() string {
+	return l.l.Name()
+}
+
+func (l *loggerWithCache) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	return l.cache.ReadLogs(config)
+}
+
+func (l *loggerWithCache) Close() error {
+	err := l.l.Close()
+	if cerr := l.cache.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func validateLogCacheOpts(cfg map[string]string) error {
+	if s, ok := cfg[DisabledOpt]; ok {
+		if _, err := strconv.ParseBool(s); err != nil {
+			return fmt.Errorf("invalid value for %s: %s", DisabledOpt, s)
+		}
+	}
+	if s, ok := cfg[MaxSizeOpt]; ok {
+		if _, err := units.FromHumanSize(s); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", MaxSizeOpt, err)
+		}
+	}
+	if s, ok := cfg[MaxFileOpt]; ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value for %s: %s", MaxFileOpt, s)
+		}
+	}
+	return nil
+}
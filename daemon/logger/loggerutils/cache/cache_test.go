@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+type countingLogger struct {
+	logged int
+	closed bool
+}
+
+func (l *countingLogger) Log(*logger.Message) error {
+	l.logged++
+	return nil
+}
+
+func (l *countingLogger) Name() string {
+	return "counting"
+}
+
+func (l *countingLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestWithLocalCache(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "log-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	driver := &countingLogger{}
+	l, err := WithLocalCache(driver, logger.Context{ContainerID: "container"}, filepath.Join(tmp, "container-cached.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "counting" {
+		t.Fatalf("expected the name of the driver, got %s", l.Name())
+	}
+	reader, ok := l.(logger.LogReader)
+	if !ok {
+		t.Fatal("expected the logs to be read back from the cache")
+	}
+
+	for _, line := range []string{"line1", "line2"} {
+		if err := l.Log(&logger.Message{Line: []byte(line), Source: "stdout", Timestamp: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if driver.logged != 2 {
+		t.Fatalf("expected the messages to be logged to the driver, got %d", driver.logged)
+	}
+
+	watcher := reader.ReadLogs(logger.ReadConfig{Tail: -1})
+	defer watcher.Close()
+	var lines []string
+	for msg := range watcher.Msg {
+		lines = append(lines, string(msg.Line))
+	}
+	if len(lines) != 2 || lines[0] != "line1\n" || lines[1] != "line2\n" {
+		t.Fatalf("unexpected logs read from the cache: %q", lines)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !driver.closed {
+		t.Fatal("expected the driver to be closed")
+	}
+}
+
+func TestWithLocalCacheDisabled(t *testing.T) {
+	driver := &countingLogger{}
+	ctx := logger.Context{Config: map[string]string{DisabledOpt: "true"}}
+	l, err := WithLocalCache(driver, ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != driver {
+		t.Fatal("expected the driver not to be wrapped when the cache is disabled")
+	}
+}
+
+func TestValidateLogCacheOpts(t *testing.T) {
+	for _, opts := range []map[string]string{
+		{DisabledOpt: "maybe"},
+		{MaxSizeOpt: "big"},
+		{MaxFileOpt: "0"},
+	} {
+		if err := logger.ValidateLogOpts("json-file", opts); err == nil {
+			t.Fatalf("expected an error validating %v", opts)
+		}
+	}
+	opts := map[string]string{DisabledOpt: "false", MaxSizeOpt: "10m", MaxFileOpt: "3", "max-size": "1m"}
+	if err := logger.ValidateLogOpts("json-file", opts); err != nil {
+		t.Fatal(err)
+	}
+}
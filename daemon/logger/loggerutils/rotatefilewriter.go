@@ -1,13 +1,22 @@
 package loggerutils
 
 import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"sync"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/pubsub"
 )
 
+// CompressedFileSuffix is appended to the name of the rotated files when
+// they are compressed.
+const CompressedFileSuffix = ".gz"
+
 // RotateFileWriter is Logger implementation for default Docker logging.
 type RotateFileWriter struct {
 	f            *os.File // store for closing
@@ -15,11 +24,15 @@ type RotateFileWriter struct {
 	capacity     int64 //maximum size of each file
 	currentSize  int64 // current size of the latest file
 	maxFiles     int   //maximum number of files
+	compress     bool  // whether the rotated files are compressed
+	compressWg   sync.WaitGroup
 	notifyRotate *pubsub.Publisher
+	rotateHook   func() error
 }
 
-//NewRotateFileWriter creates new RotateFileWriter
-func NewRotateFileWriter(logPath string, capacity int64, maxFiles int) (*RotateFileWriter, error) {
+//NewRotateFileWriter creates new RotateFileWriter. When compress is true,
+//the rotated files are gzipped in the background.
+func NewRotateFileWriter(logPath string, capacity int64, maxFiles int, compress bool) (*RotateFileWriter, error) {
 	log, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
 	if err != nil {
 		return nil, err
@@ -35,6 +48,7 @@ func NewRotateFileWriter(logPath string, capacity int64, maxFiles int) (*RotateF
 		capacity:     capacity,
 		currentSize:  size,
 		maxFiles:     maxFiles,
+		compress:     compress,
 		notifyRotate: pubsub.NewPublisher(0, 1),
 	}, nil
 }
@@ -65,6 +79,8 @@ func (w *RotateFileWriter) checkCapacityAndRotate() error {
 		if err := w.f.Close(); err != nil {
 			return err
 		}
+		// The previously rotated file must be compressed before it's moved
+		w.compressWg.Wait()
 		if err := rotate(name, w.maxFiles); err != nil {
 			return err
 		}
@@ -74,7 +90,22 @@ func (w *RotateFileWriter) checkCapacityAndRotate() error {
 		}
 		w.f = file
 		w.currentSize = 0
+		if w.rotateHook != nil {
+			if err := w.rotateHook(); err != nil {
+				return err
+			}
+		}
 		w.notifyRotate.Publish(struct{}{})
+
+		if w.compress && w.maxFiles > 1 {
+			w.compressWg.Add(1)
+			go func() {
+				defer w.compressWg.Done()
+				if err := compressFile(name + ".1"); err != nil {
+					logrus.Errorf("Error compressing rotated log file %s.1: %v", name, err)
+				}
+			}()
+		}
 	}
 
 	return nil
@@ -84,20 +115,117 @@ func rotate(name string, maxFiles int) error {
 	if maxFiles < 2 {
 		return nil
 	}
-	for i := maxFiles - 1; i > 1; i-- {
-		toPath := name + "." + strconv.Itoa(i)
-		fromPath := name + "." + strconv.Itoa(i-1)
-		if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
-			return err
+	// The rotated files are either plain or compressed
+	for _, suffix := range []string{"", CompressedFileSuffix} {
+		for i := maxFiles - 1; i > 1; i-- {
+			toPath := name + "." + strconv.Itoa(i) + suffix
+			fromPath := name + "." + strconv.Itoa(i-1) + suffix
+			if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
 		}
 	}
 
 	if err := os.Rename(name, name+".1"); err != nil && !os.IsNotExist(err) {
 		return err
 	}
+	// A compressed file left would shadow the new one
+	if err := os.Remove(name + ".1" + CompressedFileSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	return nil
 }
 
+// compressFile gzips the file and removes it. The compressed file only
+// appears once it's complete, before the file is removed, so that readers
+// always find one of them.
+func compressFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tmpName := name + CompressedFileSuffix + ".tmp"
+	out, err := os.OpenFile(tmpName, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, f)
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("error writing %s: %v", tmpName, err)
+	}
+
+	if err := os.Rename(tmpName, name+CompressedFileSuffix); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Remove(name)
+}
+
+// OpenRotatedFile opens the rotated file at pth, or its compressed version
+// decompressed into a temporary file, which is removed when it's closed.
+func OpenRotatedFile(pth string) (ReadSeekCloser, error) {
+	f, err := os.Open(pth)
+	if err == nil {
+		return f, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	gzFile, err := os.Open(pth + CompressedFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+	defer gzFile.Close()
+	gz, err := gzip.NewReader(gzFile)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tmp, err := ioutil.TempFile("", "docker-log-")
+	if err != nil {
+		return nil, err
+	}
+	df := &decompressedFile{tmp}
+	if _, err := io.Copy(tmp, gz); err != nil {
+		df.Close()
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, os.SEEK_SET); err != nil {
+		df.Close()
+		return nil, err
+	}
+	return df, nil
+}
+
+// ReadSeekCloser is a rotated file open for reading.
+type ReadSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// decompressedFile is a temporary file removed when it's closed.
+type decompressedFile struct {
+	*os.File
+}
+
+func (f *decompressedFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
+}
+
 // LogPath returns the location the given writer logs to.
 func (w *RotateFileWriter) LogPath() string {
 	return w.f.Name()
@@ -118,7 +246,24 @@ func (w *RotateFileWriter) NotifyRotateEvict(sub chan interface{}) {
 	w.notifyRotate.Evict(sub)
 }
 
-// Close closes underlying file and signals all readers to stop.
+// SetRotateHook sets a function called when the log file is rotated, once
+// the new file is created and before anything is written to it, for the
+// loggers keeping other files along with the logs.
+func (w *RotateFileWriter) SetRotateHook(hook func() error) {
+	w.mu.Lock()
+	w.rotateHook = hook
+	w.mu.Unlock()
+}
+
+// Compressed returns whether the rotated files are compressed.
+func (w *RotateFileWriter) Compressed() bool {
+	return w.compress
+}
+
+// Close closes underlying file and signals all readers to stop. It waits
+// for the rotated file to be compressed.
 func (w *RotateFileWriter) Close() error {
-	return w.f.Close()
+	err := w.f.Close()
+	w.compressWg.Wait()
+	return err
 }
@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/stringid"
+)
+
+const pluginExtName = "LogDriver"
+
+// pluginFifoDir is the directory holding the FIFOs the messages are
+// streamed to the logging plugins through.
+var pluginFifoDir = "/run/docker/logging"
+
+var errPluginClosed = errors.New("logging plugin is closed")
+
+// pluginLogEntry is a message as streamed to and read back from a logging
+// plugin. The entries are JSON encoded, one after the other.
+type pluginLogEntry struct {
+	Source   string
+	TimeNano int64
+	Line     []byte
+	Attrs    LogAttributes `json:",omitempty"`
+	Partial  bool          `json:",omitempty"`
+}
+
+// lookupPlugin returns a logging driver builder for the LogDriver plugin
+// with the given name.
+func lookupPlugin(name string) (Creator, error) {
+	pl, err := plugins.Get(name, pluginExtName)
+	if err != nil {
+		return nil, fmt.Errorf("Error looking up logging plugin %s: %v", name, err)
+	}
+	return makePluginCreator(name, &logPluginProxy{pl.Client}), nil
+}
+
+func makePluginCreator(name string, proxy *logPluginProxy) Creator {
+	return func(ctx Context) (Logger, error) {
+		if err := os.MkdirAll(pluginFifoDir, 0700); err != nil {
+			return nil, err
+		}
+		a := &pluginAdapter{
+			driverName: name,
+			fifoPath:   filepath.Join(pluginFifoDir, stringid.GenerateNonCryptoID()),
+			ctx:        ctx,
+			plugin:     proxy,
+		}
+
+		caps, err := proxy.Capabilities()
+		if err != nil {
+			// The capabilities are optional
+			logrus.Debugf("logging plugin %s has no capabilities: %v", name, err)
+		}
+
+		stream, err := openPluginStream(a.fifoPath)
+		if err != nil {
+			return nil, err
+		}
+		a.stream = stream
+		a.enc = json.NewEncoder(stream)
+
+		if err := proxy.StartLogging(a.fifoPath, ctx); err != nil {
+			a.stream.Close()
+			os.Remove(a.fifoPath)
+			return nil, fmt.Errorf("error starting logging plugin %s: %v", name, err)
+		}
+
+		if caps.ReadLogs {
+			return &pluginAdapterWithRead{a}, nil
+		}
+		return a, nil
+	}
+}
+
+// pluginAdapter is a Logger streaming the messages of a container to a
+// logging plugin through a FIFO.
+type pluginAdapter struct {
+	driverName string
+	fifoPath   string
+	ctx        Context
+	plugin     *logPluginProxy
+
+	mu     sync.Mutex // protects stream and enc
+	stream io.WriteCloser
+	enc    *json.Encoder
+}
+
+func (a *pluginAdapter) Log(msg *Message) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stream == nil {
+		return errPluginClosed
+	}
+	return a.enc.Encode(&pluginLogEntry{
+		Source:   msg.Source,
+		TimeNano: msg.Timestamp.UnixNano(),
+		Line:     msg.Line,
+		Attrs:    msg.Attrs,
+		Partial:  msg.Partial,
+	})
+}
+
+func (a *pluginAdapter) Name() string {
+	return a.driverName
+}
+
+// Close closes the stream, so that the plugin reads the messages left up
+// to the end of it, and tells the plugin to stop logging for the container.
+func (a *pluginAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stream == nil {
+		return nil
+	}
+
+	err := a.stream.Close()
+	a.stream = nil
+	if serr := a.plugin.StopLogging(a.fifoPath); err == nil {
+		err = serr
+	}
+	os.Remove(a.fifoPath)
+	return err
+}
+
+// pluginAdapterWithRead is a pluginAdapter for the plugins able to send the
+// messages back to the daemon.
+type pluginAdapterWithRead struct {
+	*pluginAdapter
+}
+
+func (a *pluginAdapterWithRead) ReadLogs(config ReadConfig) *LogWatcher {
+	watcher := NewLogWatcher()
+
+	go func() {
+		defer close(watcher.Msg)
+
+		stream, err := a.plugin.ReadLogs(a.ctx, config)
+		if err != nil {
+			watcher.Err <- fmt.Errorf("error reading logs from plugin %s: %v", a.driverName, err)
+			return
+		}
+		defer stream.Close()
+
+		// A followed stream only ends when the watcher is closed
+		done := make(chan struct{})
+		defer close(done)
+		closed := watcher.WatchClose()
+		go func() {
+			select {
+			case <-closed:
+				stream.Close()
+			case <-done:
+			}
+		}()
+
+		dec := json.NewDecoder(stream)
+		for {
+			var e pluginLogEntry
+			if err := dec.Decode(&e); err != nil {
+				select {
+				case <-closed:
+				default:
+					if err != io.EOF {
+						watcher.Err <- fmt.Errorf("error decoding logs from plugin %s: %v", a.driverName, err)
+					}
+				}
+				return
+			}
+
+			msg := &Message{
+				ContainerID: a.ctx.ContainerID,
+				Line:        e.Line,
+				Source:      e.Source,
+				Timestamp:   time.Unix(0, e.TimeNano),
+				Attrs:       e.Attrs,
+				Partial:     e.Partial,
+			}
+			if !msg.Partial {
+				msg.Line = append(msg.Line, '\n')
+			}
+			select {
+			case watcher.Msg <- msg:
+			case <-closed:
+				return
+			}
+		}
+	}()
+
+	return watcher
+}
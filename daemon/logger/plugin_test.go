@@ -0,0 +1,148 @@
+// +build !windows
+
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/go-connections/tlsconfig"
+)
+
+// fakeLogPlugin stores the entries streamed by the daemon and sends them
+// back on ReadLogs.
+type fakeLogPlugin struct {
+	mu      sync.Mutex
+	entries []pluginLogEntry
+	stopped bool
+	done    chan struct{}
+}
+
+func (p *fakeLogPlugin) serve(mux *http.ServeMux) {
+	mux.HandleFunc("/LogDriver.Capabilities", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		w.Write([]byte(`{"Cap": {"ReadLogs": true}}`))
+	})
+
+	mux.HandleFunc("/LogDriver.StartLogging", func(w http.ResponseWriter, r *http.Request) {
+		var req logPluginProxyStartLoggingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		f, err := os.Open(req.File)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		go func() {
+			defer close(p.done)
+			defer f.Close()
+			dec := json.NewDecoder(f)
+			for {
+				var e pluginLogEntry
+				if err := dec.Decode(&e); err != nil {
+					return
+				}
+				p.mu.Lock()
+				p.entries = append(p.entries, e)
+				p.mu.Unlock()
+			}
+		}()
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		w.Write([]byte(`{}`))
+	})
+
+	mux.HandleFunc("/LogDriver.StopLogging", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.stopped = true
+		p.mu.Unlock()
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		w.Write([]byte(`{}`))
+	})
+
+	mux.HandleFunc("/LogDriver.ReadLogs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-json-stream")
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		enc := json.NewEncoder(w)
+		for _, e := range p.entries {
+			enc.Encode(e)
+		}
+	})
+}
+
+func TestLogPlugin(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "logger-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	pluginFifoDir = tmp
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	p := &fakeLogPlugin{done: make(chan struct{})}
+	p.serve(mux)
+
+	u, _ := url.Parse(server.URL)
+	client, err := plugins.NewClient("tcp://"+u.Host, tlsconfig.Options{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := makePluginCreator("fake", &logPluginProxy{client})(Context{ContainerID: "container"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "fake" {
+		t.Fatalf("expected the plugin name, got %s", l.Name())
+	}
+	reader, ok := l.(LogReader)
+	if !ok {
+		t.Fatal("expected the logger to read logs back through the plugin")
+	}
+
+	now := time.Now()
+	for _, m := range []*Message{
+		{Line: []byte("line1"), Source: "stdout", Timestamp: now},
+		{Line: []byte("line2"), Source: "stderr", Timestamp: now.Add(time.Second)},
+	} {
+		if err := l.Log(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-p.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the plugin to read the stream to the end")
+	}
+	if !p.stopped {
+		t.Fatal("expected the plugin to be told to stop logging")
+	}
+
+	watcher := reader.ReadLogs(ReadConfig{Tail: -1})
+	defer watcher.Close()
+	var lines []string
+	for msg := range watcher.Msg {
+		if msg.ContainerID != "container" {
+			t.Fatalf("expected the container ID to be set, got %s", msg.ContainerID)
+		}
+		lines = append(lines, msg.Source+":"+string(msg.Line))
+	}
+	if len(lines) != 2 || lines[0] != "stdout:line1\n" || lines[1] != "stderr:line2\n" {
+		t.Fatalf("unexpected logs read back: %q", lines)
+	}
+}
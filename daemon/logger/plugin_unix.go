@@ -0,0 +1,26 @@
+// +build !windows
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// openPluginStream creates the FIFO at path and opens it for writing the
+// messages to the plugin.
+func openPluginStream(path string) (io.WriteCloser, error) {
+	if err := syscall.Mkfifo(path, 0700); err != nil {
+		return nil, fmt.Errorf("error creating fifo %s: %v", path, err)
+	}
+	// Opening the FIFO for reading as well doesn't block until the plugin
+	// opens it, and keeps it open if the plugin reopens it.
+	f, err := os.OpenFile(path, os.O_RDWR, 0700)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,12 @@
+// +build windows
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+func openPluginStream(path string) (io.WriteCloser, error) {
+	return nil, errors.New("logging plugins are not supported on this platform")
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+type pluginClient interface {
+	// Call calls the specified method with the specified arguments for the plugin.
+	Call(string, interface{}, interface{}) error
+	// Stream calls the specified method with the specified arguments for the plugin and returns the response IO stream
+	Stream(string, interface{}) (io.ReadCloser, error)
+}
+
+type logPluginProxy struct {
+	client pluginClient
+}
+
+type logPluginProxyStartLoggingRequest struct {
+	File string
+	Info Context
+}
+
+type logPluginProxyStopLoggingRequest struct {
+	File string
+}
+
+type logPluginProxyReadLogsRequest struct {
+	Info   Context
+	Config ReadConfig
+}
+
+type logPluginProxyResponse struct {
+	Err string `json:",omitempty"`
+}
+
+type logPluginProxyCapabilitiesResponse struct {
+	Cap pluginCapability
+	Err string `json:",omitempty"`
+}
+
+// pluginCapability is the set of optional features of a logging plugin.
+type pluginCapability struct {
+	ReadLogs bool
+}
+
+func (pp *logPluginProxy) StartLogging(file string, info Context) error {
+	args := &logPluginProxyStartLoggingRequest{
+		File: file,
+		Info: info,
+	}
+	var ret logPluginProxyResponse
+	if err := pp.client.Call("LogDriver.StartLogging", args, &ret); err != nil {
+		return err
+	}
+	if ret.Err != "" {
+		return errors.New(ret.Err)
+	}
+	return nil
+}
+
+func (pp *logPluginProxy) StopLogging(file string) error {
+	args := &logPluginProxyStopLoggingRequest{
+		File: file,
+	}
+	var ret logPluginProxyResponse
+	if err := pp.client.Call("LogDriver.StopLogging", args, &ret); err != nil {
+		return err
+	}
+	if ret.Err != "" {
+		return errors.New(ret.Err)
+	}
+	return nil
+}
+
+func (pp *logPluginProxy) Capabilities() (pluginCapability, error) {
+	var ret logPluginProxyCapabilitiesResponse
+	if err := pp.client.Call("LogDriver.Capabilities", nil, &ret); err != nil {
+		return pluginCapability{}, err
+	}
+	if ret.Err != "" {
+		return pluginCapability{}, errors.New(ret.Err)
+	}
+	return ret.Cap, nil
+}
+
+func (pp *logPluginProxy) ReadLogs(info Context, config ReadConfig) (io.ReadCloser, error) {
+	args := &logPluginProxyReadLogsRequest{
+		Info:   info,
+		Config: config,
+	}
+	return pp.client.Stream("LogDriver.ReadLogs", args)
+}
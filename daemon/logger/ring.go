@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// ModeOpt is the log option selecting how the messages are delivered to
+	// the logging driver.
+	ModeOpt = "mode"
+	// MaxBufferSizeOpt is the log option setting the size of the buffer of
+	// the non-blocking mode.
+	MaxBufferSizeOpt = "max-buffer-size"
+
+	// ModeBlocking delivers the messages directly to the logging driver, the
+	// container blocks when the driver does. This is the default mode.
+	ModeBlocking = "blocking"
+	// ModeNonBlocking buffers the messages in a RingLogger.
+	ModeNonBlocking = "non-blocking"
+
+	defaultRingMaxSize = 1e6 // 1MB
+)
+
+var errRingClosed = errors.New("logger is closed")
+
+// DroppedMessagesCounter is implemented by the loggers that may drop
+// messages rather than block, such as the RingLogger.
+type DroppedMessagesCounter interface {
+	// DroppedMessages returns the number of messages dropped so far.
+	DroppedMessages() uint64
+}
+
+// RingLogger is a Logger that buffers the messages and writes them to the
+// wrapped logging driver in the background, so that a stalled driver never
+// blocks the container. When the buffer is full, the oldest messages are
+// dropped to make room for the new ones.
+type RingLogger struct {
+	buffer *messageRing
+	l      Logger
+	done   chan struct{}
+}
+
+type ringWithReader struct {
+	*RingLogger
+}
+
+func (r *ringWithReader) ReadLogs(cfg ReadConfig) *LogWatcher {
+	return r.l.(LogReader).ReadLogs(cfg)
+}
+
+// NewRingLogger wraps the driver in a RingLogger buffering up to maxSize
+// bytes of messages. A negative maxSize selects the default size. The
+// returned logger reads the logs back if the driver does.
+func NewRingLogger(driver Logger, maxSize int64) Logger {
+	if maxSize < 0 {
+		maxSize = defaultRingMaxSize
+	}
+	l := &RingLogger{
+		buffer: newRing(maxSize),
+		l:      driver,
+		done:   make(chan struct{}),
+	}
+	go l.run()
+	if _, ok := driver.(LogReader); ok {
+		return &ringWithReader{l}
+	}
+	return l
+}
+
+// Log queues the message to be written to the logging driver. It doesn't
+// block, dropping the oldest messages if the buffer is full.
+func (r *RingLogger) Log(msg *Message) error {
+	return r.buffer.Enqueue(msg)
+}
+
+// Name returns the name of the wrapped logging driver.
+func (r *RingLogger) Name() string {
+	return r.l.Name()
+}
+
+// DroppedMessages returns the number of messages dropped because the buffer
+// was full.
+func (r *RingLogger) DroppedMessages() uint64 {
+	return r.buffer.Dropped()
+}
+
+// Close writes the messages left in the buffer to the logging driver and
+// closes it.
+func (r *RingLogger) Close() error {
+	r.buffer.Close()
+	<-r.done
+
+	for _, msg := range r.buffer.Drain() {
+		if err := r.l.Log(msg); err != nil {
+			logrus.Debugf("failed to flush the buffered messages to the %s logging driver: %v", r.l.Name(), err)
+			break
+		}
+	}
+	return r.l.Close()
+}
+
+func (r *RingLogger) run() {
+	defer close(r.done)
+	for {
+		msg, err := r.buffer.Dequeue()
+		if err != nil {
+			return
+		}
+		if err := r.l.Log(msg); err != nil {
+			logrus.Debugf("failed to write a message to the %s logging driver: %v", r.l.Name(), err)
+		}
+	}
+}
+
+// messageRing is a queue of messages bounded by the total size of their
+// lines.
+type messageRing struct {
+	mu   sync.Mutex
+	wait *sync.Cond
+
+	queue     []*Message
+	sizeBytes int64
+	maxBytes  int64
+	closed    bool
+	dropped   uint64
+}
+
+func newRing(maxBytes int64) *messageRing {
+	r := &messageRing{maxBytes: maxBytes}
+	r.wait = sync.NewCond(&r.mu)
+	return r
+}
+
+// Enqueue adds the message to the queue, dropping the oldest messages
+// until it fits. A message larger than the whole queue is still added once
+// the queue is empty.
+func (r *messageRing) Enqueue(m *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errRingClosed
+	}
+
+	size := int64(len(m.Line))
+	for len(r.queue) > 0 && r.sizeBytes+size > r.maxBytes {
+		r.sizeBytes -= int64(len(r.queue[0].Line))
+		r.queue[0] = nil
+		r.queue = r.queue[1:]
+		r.dropped++
+	}
+	r.queue = append(r.queue, m)
+	r.sizeBytes += size
+	r.wait.Signal()
+	return nil
+}
+
+// Dequeue removes the oldest message from the queue, waiting for one if the
+// queue is empty. It returns an error once the queue is closed.
+func (r *messageRing) Dequeue() (*Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(r.queue) == 0 && !r.closed {
+		r.wait.Wait()
+	}
+	if r.closed {
+		return nil, errRingClosed
+	}
+
+	m := r.queue[0]
+	r.queue[0] = nil
+	r.queue = r.queue[1:]
+	r.sizeBytes -= int64(len(m.Line))
+	return m, nil
+}
+
+// Drain removes all the messages from the queue.
+func (r *messageRing) Drain() []*Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msgs := r.queue
+	r.queue = nil
+	r.sizeBytes = 0
+	return msgs
+}
+
+// Dropped returns the number of messages dropped by Enqueue.
+func (r *messageRing) Dropped() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// Close makes Dequeue return and Enqueue fail. The messages left can be
+// drained.
+func (r *messageRing) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	r.wait.Broadcast()
+}
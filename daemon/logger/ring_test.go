@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// blockingLogger records the messages, blocking until it's unblocked.
+type blockingLogger struct {
+	mu      sync.Mutex
+	unblock chan struct{}
+	lines   []string
+	closed  bool
+}
+
+func (l *blockingLogger) Log(msg *Message) error {
+	<-l.unblock
+	l.mu.Lock()
+	l.lines = append(l.lines, string(msg.Line))
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *blockingLogger) Name() string {
+	return "blocking"
+}
+
+func (l *blockingLogger) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+	return nil
+}
+
+func TestRingLoggerDropsOldest(t *testing.T) {
+	driver := &blockingLogger{unblock: make(chan struct{})}
+	l := NewRingLogger(driver, 10)
+	if _, ok := l.(LogReader); ok {
+		t.Fatal("expected the ring logger not to read logs when the driver doesn't")
+	}
+
+	// The logger never blocks, though the driver does. At most one message
+	// is held by the driver, the buffer holds 5 of the others.
+	for i := 0; i < 10; i++ {
+		if err := l.Log(&Message{Line: []byte(fmt.Sprintf("m%d", i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dropped := l.(DroppedMessagesCounter).DroppedMessages()
+	if dropped != 4 && dropped != 5 {
+		t.Fatalf("expected 4 or 5 dropped messages, got %d", dropped)
+	}
+
+	close(driver.unblock)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !driver.closed {
+		t.Fatal("expected the driver to be closed")
+	}
+	if uint64(len(driver.lines))+dropped != 10 {
+		t.Fatalf("expected all the messages not dropped to be logged, got %v with %d dropped", driver.lines, dropped)
+	}
+	if last := driver.lines[len(driver.lines)-1]; last != "m9" {
+		t.Fatalf("expected the newest message to be kept, got %s", last)
+	}
+	if err := l.Log(&Message{Line: []byte("late")}); err == nil {
+		t.Fatal("expected an error logging to a closed logger")
+	}
+}
+
+func TestValidateLogOptsMode(t *testing.T) {
+	for _, opts := range []map[string]string{
+		{ModeOpt: "invalid"},
+		{MaxBufferSizeOpt: "1m"},
+		{ModeOpt: ModeBlocking, MaxBufferSizeOpt: "1m"},
+		{ModeOpt: ModeNonBlocking, MaxBufferSizeOpt: "invalid"},
+	} {
+		if err := ValidateLogOpts("unvalidated", opts); err == nil {
+			t.Fatalf("expected an error validating %v", opts)
+		}
+	}
+
+	for _, opts := range []map[string]string{
+		{ModeOpt: ModeBlocking},
+		{ModeOpt: ModeNonBlocking},
+		{ModeOpt: ModeNonBlocking, MaxBufferSizeOpt: "4m"},
+	} {
+		if err := ValidateLogOpts("unvalidated", opts); err != nil {
+			t.Fatalf("unexpected error validating %v: %v", opts, err)
+		}
+	}
+}
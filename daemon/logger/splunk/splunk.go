@@ -136,7 +136,6 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		return nil, err
 	}
 	nullMessage.Event.Tag = tag
-	nullMessage.Event.Attrs = ctx.ExtraAttributes(nil)
 
 	logger := &splunkLogger{
 		client:      client,
@@ -160,6 +159,7 @@ func (l *splunkLogger) Log(msg *logger.Message) error {
 	message.Time = fmt.Sprintf("%f", float64(msg.Timestamp.UnixNano())/1000000000)
 	message.Event.Line = string(msg.Line)
 	message.Event.Source = msg.Source
+	message.Event.Attrs = msg.Attrs
 
 	jsonEvent, err := json.Marshal(&message)
 	if err != nil {
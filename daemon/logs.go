@@ -35,12 +35,16 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	if err != nil {
 		return err
 	}
+	if cLog != container.LogDriver {
+		// The logger was only started to read the logs, close it so that
+		// it doesn't leak, e.g. the stream to a logging plugin.
+		defer cLog.Close()
+	}
 	logReader, ok := cLog.(logger.LogReader)
 	if !ok {
 		return logger.ErrReadLogsNotSupported
 	}
 
-	follow := config.Follow && container.IsRunning()
 	tailLines, err := strconv.Atoi(config.Tail)
 	if err != nil {
 		tailLines = -1
@@ -48,7 +52,7 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 
 	logrus.Debug("logs: begin stream")
 
-	var since time.Time
+	var since, until time.Time
 	if config.Since != "" {
 		s, n, err := timetypes.ParseTimestamps(config.Since, 0)
 		if err != nil {
@@ -56,8 +60,18 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 		}
 		since = time.Unix(s, n)
 	}
+	if config.Until != "" {
+		s, n, err := timetypes.ParseTimestamps(config.Until, 0)
+		if err != nil {
+			return err
+		}
+		until = time.Unix(s, n)
+	}
+	// There is nothing to follow once the end of the window has passed.
+	follow := config.Follow && container.IsRunning() && (until.IsZero() || until.After(time.Now()))
 	readConfig := logger.ReadConfig{
 		Since:  since,
+		Until:  until,
 		Tail:   tailLines,
 		Follow: follow,
 	}
@@ -75,6 +89,9 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 		outStream = stdcopy.NewStdWriter(outStream, stdcopy.Stdout)
 	}
 
+	// The sources in the middle of a line split in partial messages, which
+	// are joined back by leaving out the timestamps of the parts that follow.
+	partial := make(map[string]bool)
 	for {
 		select {
 		case err := <-logs.Err:
@@ -90,7 +107,7 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 				return nil
 			}
 			logLine := msg.Line
-			if config.Timestamps {
+			if config.Timestamps && !partial[msg.Source] {
 				logLine = append([]byte(msg.Timestamp.Format(logger.TimeFormat)+" "), logLine...)
 			}
 			if msg.Source == "stdout" && config.ShowStdout {
@@ -99,6 +116,7 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 			if msg.Source == "stderr" && config.ShowStderr {
 				errStream.Write(logLine)
 			}
+			partial[msg.Source] = msg.Partial
 		}
 	}
 }
@@ -108,6 +126,9 @@ func (daemon *Daemon) getLogger(container *container.Container) (logger.Logger,
 		return container.LogDriver, nil
 	}
 	cfg := daemon.getLogConfig(container.HostConfig.LogConfig)
+	if cfg.Type == "none" {
+		return nil, logger.ErrReadLogsNotSupported
+	}
 	if err := logger.ValidateLogOpts(cfg.Type, cfg.Config); err != nil {
 		return nil, err
 	}
@@ -129,7 +150,7 @@ func (daemon *Daemon) StartLogging(container *container.Container) error {
 		return fmt.Errorf("Failed to initialize logging driver: %v", err)
 	}
 
-	copier := logger.NewCopier(container.ID, map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l)
+	copier := logger.NewCopier(container.ID, map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l, container.LogAttributes(cfg))
 	container.LogCopier = copier
 	copier.Run()
 	container.LogDriver = l
@@ -142,6 +163,15 @@ func (daemon *Daemon) StartLogging(container *container.Container) error {
 	return nil
 }
 
+// logDroppedMessages returns the number of messages the logger of the
+// running container dropped in non-blocking mode.
+func logDroppedMessages(container *container.Container) uint64 {
+	if d, ok := container.LogDriver.(logger.DroppedMessagesCounter); ok {
+		return d.DroppedMessages()
+	}
+	return 0
+}
+
 // getLogConfig returns the log configuration for the container.
 func (daemon *Daemon) getLogConfig(cfg containertypes.LogConfig) containertypes.LogConfig {
 	if cfg.Type != "" || len(cfg.Config) > 0 { // container has log driver configured
@@ -152,5 +182,13 @@ func (daemon *Daemon) getLogConfig(cfg containertypes.LogConfig) containertypes.
 	}
 
 	// Use daemon's default log config for containers
+	return daemon.getDefaultLogConfig()
+}
+
+// getDefaultLogConfig returns the daemon's default log configuration. It
+// can change when the daemon configuration is reloaded.
+func (daemon *Daemon) getDefaultLogConfig() containertypes.LogConfig {
+	daemon.defaultLogConfigLock.Lock()
+	defer daemon.defaultLogConfigLock.Unlock()
 	return daemon.defaultLogConfig
 }
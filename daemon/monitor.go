@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/runconfig"
 )
@@ -32,6 +33,7 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		c.Wait()
 		c.Reset(false)
 		c.SetStopped(platformConstructExitStatus(e))
+		daemon.updateHealthMonitor(c)
 		attributes := map[string]string{
 			"exitCode": strconv.Itoa(int(e.ExitCode)),
 		}
@@ -49,7 +51,9 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		defer c.Unlock()
 		c.Reset(false)
 		c.RestartCount++
+		c.LastRestartReason = restartReason(c, e.ExitCode)
 		c.SetRestarting(platformConstructExitStatus(e))
+		daemon.updateHealthMonitor(c)
 		attributes := map[string]string{
 			"exitCode": strconv.Itoa(int(e.ExitCode)),
 		}
@@ -79,6 +83,7 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 	case libcontainerd.StateStart, libcontainerd.StateRestore:
 		c.SetRunning(int(e.Pid), e.State == libcontainerd.StateStart)
 		c.HasBeenManuallyStopped = false
+		daemon.initHealthMonitor(c, e.State == libcontainerd.StateStart)
 		if err := c.ToDisk(); err != nil {
 			c.Reset(false)
 			return err
@@ -86,15 +91,26 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		daemon.LogContainerEvent(c, "start")
 	case libcontainerd.StatePause:
 		c.Paused = true
+		daemon.updateHealthMonitor(c)
 		daemon.LogContainerEvent(c, "pause")
 	case libcontainerd.StateResume:
 		c.Paused = false
+		daemon.updateHealthMonitor(c)
 		daemon.LogContainerEvent(c, "unpause")
 	}
 
 	return nil
 }
 
+// restartReason describes why the restart policy is restarting c.
+// Called with c locked.
+func restartReason(c *container.Container, exitCode uint32) string {
+	if shouldRestartUnhealthy(c) {
+		return fmt.Sprintf("unhealthy after %d consecutive failed health checks", c.State.Health.FailingStreak)
+	}
+	return fmt.Sprintf("exited with code %d", exitCode)
+}
+
 // AttachStreams is called by libcontainerd to connect the stdio.
 func (daemon *Daemon) AttachStreams(id string, iop libcontainerd.IOPipe) error {
 	var s *runconfig.StreamConfig
@@ -0,0 +1,268 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
+	timetypes "github.com/docker/engine-api/types/time"
+)
+
+var (
+	acceptedContainersPruneFilters = map[string]bool{
+		"until": true,
+		"label": true,
+	}
+	acceptedImagesPruneFilters = map[string]bool{
+		"dangling": true,
+		"until":    true,
+		"label":    true,
+	}
+	acceptedVolumesPruneFilters = map[string]bool{
+		"label": true,
+	}
+	acceptedNetworksPruneFilters = map[string]bool{
+		"label": true,
+	}
+)
+
+// ContainersPrune removes the containers that are not running and match
+// the given filters.
+func (daemon *Daemon) ContainersPrune(pruneFilters filters.Args) (*types.ContainersPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedContainersPruneFilters); err != nil {
+		return nil, err
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &types.ContainersPruneReport{}
+	for _, c := range daemon.List() {
+		if c.IsRunning() || c.IsRestarting() {
+			continue
+		}
+		if !until.IsZero() && !c.Created.Before(until) {
+			continue
+		}
+		if !pruneFilters.MatchKVList("label", c.Config.Labels) {
+			continue
+		}
+
+		sizeRw, _ := daemon.getSize(c)
+		if err := daemon.ContainerRm(c.ID, &types.ContainerRmConfig{}); err != nil {
+			logrus.Warnf("failed to prune container %s: %v", c.ID, err)
+			continue
+		}
+		if sizeRw > 0 {
+			rep.SpaceReclaimed += uint64(sizeRw)
+		}
+		rep.ContainersDeleted = append(rep.ContainersDeleted, c.ID)
+	}
+
+	return rep, nil
+}
+
+// VolumesPrune removes the volumes that are not referenced by any container
+// and match the given filters.
+func (daemon *Daemon) VolumesPrune(pruneFilters filters.Args) (*types.VolumesPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedVolumesPruneFilters); err != nil {
+		return nil, err
+	}
+
+	vols, _, err := daemon.volumes.List()
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &types.VolumesPruneReport{}
+	for _, v := range daemon.volumes.FilterByUsed(vols, false) {
+		var labels map[string]string
+		if lv, ok := v.(interface {
+			Labels() map[string]string
+		}); ok {
+			labels = lv.Labels()
+		}
+		if !pruneFilters.MatchKVList("label", labels) {
+			continue
+		}
+
+		var size int64
+		if v.DriverName() == volume.DefaultDriverName {
+			if size, err = directory.Size(v.Path()); err != nil {
+				logrus.Warnf("could not determine size of volume %s: %v", v.Name(), err)
+			}
+		}
+		if err := daemon.VolumeRm(v.Name()); err != nil {
+			logrus.Warnf("failed to prune volume %s: %v", v.Name(), err)
+			continue
+		}
+		if size > 0 {
+			rep.SpaceReclaimed += uint64(size)
+		}
+		rep.VolumesDeleted = append(rep.VolumesDeleted, v.Name())
+	}
+
+	return rep, nil
+}
+
+// ImagesPrune removes the images that are not used by any container and
+// match the given filters. Unless the dangling filter is set to false, only
+// the dangling images are removed.
+func (daemon *Daemon) ImagesPrune(pruneFilters filters.Args) (*types.ImagesPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedImagesPruneFilters); err != nil {
+		return nil, err
+	}
+
+	danglingOnly := true
+	if pruneFilters.Include("dangling") {
+		if pruneFilters.ExactMatch("dangling", "false") || pruneFilters.ExactMatch("dangling", "0") {
+			danglingOnly = false
+		} else if !pruneFilters.ExactMatch("dangling", "true") && !pruneFilters.ExactMatch("dangling", "1") {
+			return nil, fmt.Errorf("Invalid filter 'dangling=%s'", pruneFilters.Get("dangling"))
+		}
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	var allImages map[image.ID]*image.Image
+	if danglingOnly {
+		allImages = daemon.imageStore.Heads()
+	} else {
+		allImages = daemon.imageStore.Map()
+	}
+
+	usedImages := make(map[image.ID]bool)
+	for _, c := range daemon.List() {
+		usedImages[c.ImageID] = true
+	}
+
+	// Record the size of the layers of the images that may be removed, as
+	// they can't be looked up once they are gone.
+	layerSizes := make(map[layer.ChainID]int64)
+	var candidates []image.ID
+	for id, img := range allImages {
+		if usedImages[id] || len(daemon.imageStore.Children(id)) != 0 {
+			continue
+		}
+		if danglingOnly && len(daemon.referenceStore.References(id)) != 0 {
+			continue
+		}
+		if !until.IsZero() && !img.Created.Before(until) {
+			continue
+		}
+		if img.Config == nil && pruneFilters.Include("label") {
+			continue
+		}
+		if img.Config != nil && !pruneFilters.MatchKVList("label", img.Config.Labels) {
+			continue
+		}
+		for _, chainID := range layerChain(img) {
+			if _, ok := layerSizes[chainID]; ok {
+				continue
+			}
+			size, err := daemon.layerDiffSize(chainID)
+			if err != nil {
+				return nil, err
+			}
+			layerSizes[chainID] = size
+		}
+		candidates = append(candidates, id)
+	}
+
+	rep := &types.ImagesPruneReport{}
+	for _, id := range candidates {
+		refs := daemon.referenceStore.References(id)
+		if len(refs) == 0 {
+			deleted, err := daemon.ImageDelete(id.String(), false, true)
+			if err != nil {
+				logrus.Warnf("failed to prune image %s: %v", id, err)
+				continue
+			}
+			rep.ImagesDeleted = append(rep.ImagesDeleted, deleted...)
+			continue
+		}
+		for _, ref := range refs {
+			deleted, err := daemon.ImageDelete(ref.String(), false, true)
+			if err != nil {
+				logrus.Warnf("failed to prune image %s: %v", ref, err)
+				break
+			}
+			rep.ImagesDeleted = append(rep.ImagesDeleted, deleted...)
+		}
+	}
+
+	// The space reclaimed is the size of the layers that no image uses anymore
+	if len(rep.ImagesDeleted) > 0 {
+		for _, img := range daemon.imageStore.Map() {
+			for _, chainID := range layerChain(img) {
+				delete(layerSizes, chainID)
+			}
+		}
+		for _, size := range layerSizes {
+			rep.SpaceReclaimed += uint64(size)
+		}
+	}
+
+	return rep, nil
+}
+
+// NetworksPrune removes the networks that have no endpoints and match the
+// given filters. The pre-defined networks are never removed.
+func (daemon *Daemon) NetworksPrune(pruneFilters filters.Args) (*types.NetworksPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedNetworksPruneFilters); err != nil {
+		return nil, err
+	}
+
+	rep := &types.NetworksPruneReport{}
+	if !daemon.NetworkControllerEnabled() {
+		return rep, nil
+	}
+	for _, nw := range daemon.getAllNetworks() {
+		if runconfig.IsPreDefinedNetwork(nw.Name()) || len(nw.Endpoints()) > 0 {
+			continue
+		}
+		if !pruneFilters.MatchKVList("label", nw.Info().Labels()) {
+			continue
+		}
+		if err := daemon.DeleteNetwork(nw.ID()); err != nil {
+			logrus.Warnf("failed to prune network %s: %v", nw.ID(), err)
+			continue
+		}
+		rep.NetworksDeleted = append(rep.NetworksDeleted, nw.ID())
+	}
+
+	return rep, nil
+}
+
+// getUntilFromPruneFilters returns the time set with the until filter, or the
+// zero time if there is none. The value can be a timestamp or a duration
+// relative to now, as in `until=24h`.
+func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
+	until := time.Time{}
+	if !pruneFilters.Include("until") {
+		return until, nil
+	}
+	untilFilters := pruneFilters.Get("until")
+	if len(untilFilters) > 1 {
+		return until, fmt.Errorf("more than one until filter specified")
+	}
+	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
+	if err != nil {
+		return until, err
+	}
+	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return until, err
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
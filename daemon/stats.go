@@ -99,8 +99,17 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 						TxDropped: txDropped,
 					},
 				}
-			} else {
+			} else if apiVersion.LessThan("1.24") {
 				statsJSON = statsJSONPost120
+			} else {
+				container.Lock()
+				dropped := logDroppedMessages(container)
+				container.Unlock()
+
+				// The stats are shared by all the subscribers
+				s := *statsJSONPost120
+				s.Logs = &types.LogStats{DroppedMessages: dropped}
+				statsJSON = &s
 			}
 
 			if !config.Stream && noStreamFirstFrame {
@@ -43,6 +43,16 @@ func (f fallbackError) Error() string {
 	return f.err.Error()
 }
 
+// mirrorFallbackError wraps an error of a mirror so that the pull falls back
+// to the next endpoint whatever the error is. The failure of a mirror
+// doesn't confirm anything about the registry it mirrors.
+func mirrorFallbackError(err error) error {
+	if fallbackErr, ok := err.(fallbackError); ok {
+		return fallbackError{err: fallbackErr.err, transportOK: fallbackErr.transportOK}
+	}
+	return fallbackError{err: err}
+}
+
 // shouldV2Fallback returns true if this error is a reason to fall back to v1.
 func shouldV2Fallback(err errcode.Error) bool {
 	switch err.Code {
@@ -39,6 +39,9 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// PartialBlobStore keeps the partially downloaded blobs, to resume
+	// their download. The blobs are downloaded to temporary files if nil.
+	PartialBlobStore *xfer.PartialBlobStore
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -222,6 +222,7 @@ func (p *v1Puller) pullImage(ctx context.Context, v1ID, endpoint string, localNa
 			v1LayerID:        v1LayerID,
 			indexName:        p.repoInfo.Index.Name,
 			endpoint:         endpoint,
+			registryHost:     p.endpoint.URL.Host,
 			v1IDService:      p.v1IDService,
 			layersDownloaded: layersDownloaded,
 			layerSize:        imgSize,
@@ -280,6 +281,7 @@ type v1LayerDescriptor struct {
 	v1LayerID        string
 	indexName        string
 	endpoint         string
+	registryHost     string
 	v1IDService      *metadata.V1IDService
 	layersDownloaded *bool
 	layerSize        int64
@@ -291,6 +293,10 @@ func (ld *v1LayerDescriptor) Key() string {
 	return "v1:" + ld.v1LayerID
 }
 
+func (ld *v1LayerDescriptor) Registry() string {
+	return ld.registryHost
+}
+
 func (ld *v1LayerDescriptor) ID() string {
 	return stringid.TruncateID(ld.v1LayerID)
 }
@@ -61,10 +61,17 @@ func (p *v2Puller) Pull(ctx context.Context, ref reference.Named) (err error) {
 	p.repo, p.confirmedV2, err = NewV2Repository(ctx, p.repoInfo, p.endpoint, p.config.MetaHeaders, p.config.AuthConfig, "pull")
 	if err != nil {
 		logrus.Warnf("Error getting v2 registry: %v", err)
+		if p.endpoint.Mirror {
+			return mirrorFallbackError(err)
+		}
 		return err
 	}
 
 	if err = p.pullV2Repository(ctx, ref); err != nil {
+		if p.endpoint.Mirror {
+			logrus.Errorf("Error trying v2 mirror %s: %v", p.endpoint.URL, err)
+			return mirrorFallbackError(err)
+		}
 		if _, ok := err.(fallbackError); ok {
 			return err
 		}
@@ -130,15 +137,24 @@ type v2LayerDescriptor struct {
 	digest            digest.Digest
 	repoInfo          *registry.RepositoryInfo
 	repo              distribution.Repository
+	registryHost      string
 	V2MetadataService *metadata.V2MetadataService
+	partialBlobs      *xfer.PartialBlobStore
 	tmpFile           *os.File
-	verifier          digest.Verifier
+	// partial is set if tmpFile is the partial download of the blob, kept
+	// in partialBlobs to resume the download later on.
+	partial  bool
+	verifier digest.Verifier
 }
 
 func (ld *v2LayerDescriptor) Key() string {
 	return "v2:" + ld.digest.String()
 }
 
+func (ld *v2LayerDescriptor) Registry() string {
+	return ld.registryHost
+}
+
 func (ld *v2LayerDescriptor) ID() string {
 	return stringid.TruncateID(ld.digest.String())
 }
@@ -156,27 +172,26 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	)
 
 	if ld.tmpFile == nil {
-		ld.tmpFile, err = createDownloadFile()
+		ld.tmpFile, err = ld.openDownloadFile()
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
-	} else {
-		offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
-		if err != nil {
-			logrus.Debugf("error seeking to end of download file: %v", err)
-			offset = 0
+	}
+	// The download file holds the data of the previous attempts, or of a
+	// previous pull if it's a partial blob.
+	offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
+	if err != nil {
+		logrus.Debugf("error seeking to end of download file: %v", err)
+		offset = 0
 
-			ld.tmpFile.Close()
-			if err := os.Remove(ld.tmpFile.Name()); err != nil {
-				logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-			}
-			ld.tmpFile, err = createDownloadFile()
-			if err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-		} else if offset != 0 {
-			logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
+		ld.closeDownloadFile(ld.tmpFile, false)
+		ld.verifier = nil
+		ld.tmpFile, err = ld.openDownloadFile()
+		if err != nil {
+			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+	} else if offset != 0 {
+		logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
 	}
 
 	tmpFile := ld.tmpFile
@@ -232,6 +247,19 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+		// The data of a partial blob downloaded by a previous pull is
+		// verified along with the rest of the blob.
+		if offset != 0 {
+			if _, err := tmpFile.Seek(0, os.SEEK_SET); err != nil {
+				return nil, 0, xfer.DoNotRetry{Err: err}
+			}
+			if _, err := io.CopyN(ld.verifier, tmpFile, offset); err != nil {
+				if err := ld.truncateDownloadFile(); err != nil {
+					return nil, 0, xfer.DoNotRetry{Err: err}
+				}
+				return nil, 0, err
+			}
+		}
 	}
 
 	_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
@@ -260,6 +288,10 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 			return nil, 0, err
 		}
+		// Don't keep the data to resume the download from
+		if err := ld.truncateDownloadFile(); err != nil {
+			logrus.Errorf("Failed to truncate the download file of %s: %v", ld.digest, err)
+		}
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
 
@@ -269,10 +301,7 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 	_, err = tmpFile.Seek(0, os.SEEK_SET)
 	if err != nil {
-		tmpFile.Close()
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
+		ld.closeDownloadFile(tmpFile, false)
 		ld.tmpFile = nil
 		ld.verifier = nil
 		return nil, 0, xfer.DoNotRetry{Err: err}
@@ -283,22 +312,54 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	ld.tmpFile = nil
 
 	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
-		tmpFile.Close()
-		err := os.RemoveAll(tmpFile.Name())
-		if err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		return err
+		return ld.closeDownloadFile(tmpFile, false)
 	}), size, nil
 }
 
 func (ld *v2LayerDescriptor) Close() {
 	if ld.tmpFile != nil {
-		ld.tmpFile.Close()
-		if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
+		// The download failed, keep what was downloaded to resume it
+		ld.closeDownloadFile(ld.tmpFile, true)
+	}
+}
+
+// openDownloadFile opens the file to download the blob to. It's the partial
+// download of the blob if the partial blobs are kept, to resume the download
+// of a previous pull, or a temporary file otherwise.
+func (ld *v2LayerDescriptor) openDownloadFile() (*os.File, error) {
+	if ld.partialBlobs != nil {
+		f, err := ld.partialBlobs.Open(ld.digest)
+		if err == nil {
+			ld.partial = true
+			return f, nil
 		}
+		logrus.Debugf("Not keeping the partial download of %s: %v", ld.digest, err)
 	}
+	ld.partial = false
+	return createDownloadFile()
+}
+
+// closeDownloadFile closes the download file, and removes it unless it's a
+// partial blob to keep, which isn't empty.
+func (ld *v2LayerDescriptor) closeDownloadFile(f *os.File, keep bool) error {
+	if ld.partial {
+		if keep {
+			if fi, err := f.Stat(); err == nil && fi.Size() == 0 {
+				keep = false
+			}
+		}
+		err := ld.partialBlobs.Release(ld.digest, f, !keep)
+		if err != nil {
+			logrus.Errorf("Failed to release the partial download of %s: %v", ld.digest, err)
+		}
+		return err
+	}
+	f.Close()
+	err := os.RemoveAll(f.Name())
+	if err != nil {
+		logrus.Errorf("Failed to remove temp file: %s", f.Name())
+	}
+	return err
 }
 
 func (ld *v2LayerDescriptor) truncateDownloadFile() error {
@@ -361,7 +422,11 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdat
 	p.confirmedV2 = true
 
 	logrus.Debugf("Pulling ref from V2 registry: %s", ref.String())
-	progress.Message(p.config.ProgressOutput, tagOrDigest, "Pulling from "+p.repo.Named().Name())
+	if p.endpoint.Mirror {
+		progress.Message(p.config.ProgressOutput, tagOrDigest, "Pulling from "+p.repo.Named().Name()+" through mirror "+p.endpoint.URL.String())
+	} else {
+		progress.Message(p.config.ProgressOutput, tagOrDigest, "Pulling from "+p.repo.Named().Name())
+	}
 
 	var (
 		imageID        image.ID
@@ -460,7 +525,9 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 			digest:            blobSum,
 			repoInfo:          p.repoInfo,
 			repo:              p.repo,
+			registryHost:      p.endpoint.URL.Host,
 			V2MetadataService: p.V2MetadataService,
+			partialBlobs:      p.config.PartialBlobStore,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -526,7 +593,9 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 			digest:            d.Digest,
 			repo:              p.repo,
 			repoInfo:          p.repoInfo,
+			registryHost:      p.endpoint.URL.Host,
 			V2MetadataService: p.V2MetadataService,
+			partialBlobs:      p.config.PartialBlobStore,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -1,16 +1,27 @@
 package distribution
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/distribution/digest"
 	"github.com/docker/distribution/manifest/schema1"
+	distreference "github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/distribution/xfer"
+	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
+	"golang.org/x/net/context"
 )
 
 // TestFixManifestLayers checks that fixManifestLayers removes a duplicate
@@ -181,3 +192,89 @@ func TestValidateManifest(t *testing.T) {
 		t.Fatal("expected validateManifest to fail with digest error")
 	}
 }
+
+// TestResumePartialBlob checks that the download of a blob resumes from its
+// partial download, and that the whole blob is verified.
+func TestResumePartialBlob(t *testing.T) {
+	blob := bytes.Repeat([]byte("layer data"), 1000)
+	dgst := digest.FromBytes(blob)
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/foo/blobs/"+dgst.String() {
+			http.NotFound(w, r)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+	}))
+	defer server.Close()
+
+	named, err := distreference.ParseNamed("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := client.NewRepository(context.Background(), named, server.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp, err := ioutil.TempDir("", "partial-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	partialBlobs, err := xfer.NewPartialBlobStore(tmp, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A previous pull downloaded the first half of the blob
+	f, err := partialBlobs.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(blob[:len(blob)/2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := partialBlobs.Release(dgst, f, false); err != nil {
+		t.Fatal(err)
+	}
+
+	progressChan := make(chan progress.Progress, 100)
+	go func() {
+		for range progressChan {
+		}
+	}()
+	defer close(progressChan)
+
+	ld := &v2LayerDescriptor{digest: dgst, repo: repo, partialBlobs: partialBlobs}
+	rc, _, err := ld.Download(context.Background(), progress.ChanOutput(progressChan))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ld.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, blob) {
+		t.Fatal("the downloaded blob differs from the blob")
+	}
+	resumed := false
+	for _, r := range ranges {
+		if r == fmt.Sprintf("bytes=%d-", len(blob)/2) {
+			resumed = true
+		}
+	}
+	if !resumed {
+		t.Fatalf("expected the download to resume with a range request, got %q", ranges)
+	}
+	if files, err := ioutil.ReadDir(tmp); err != nil || len(files) != 0 {
+		t.Fatalf("expected the partial blob to be removed once downloaded, got %v (%v)", files, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -23,16 +24,65 @@ const maxDownloadAttempts = 5
 type LayerDownloadManager struct {
 	layerStore layer.Store
 	tm         TransferManager
+
+	mu sync.Mutex
+	// registryTMs are the transfer managers of the registries with a
+	// concurrency limit of their own, by registry host.
+	registryTMs map[string]TransferManager
 }
 
 // NewLayerDownloadManager returns a new LayerDownloadManager.
 func NewLayerDownloadManager(layerStore layer.Store, concurrencyLimit int) *LayerDownloadManager {
 	return &LayerDownloadManager{
-		layerStore: layerStore,
-		tm:         NewTransferManager(concurrencyLimit),
+		layerStore:  layerStore,
+		tm:          NewTransferManager(concurrencyLimit),
+		registryTMs: make(map[string]TransferManager),
 	}
 }
 
+// SetConcurrency sets the max concurrent downloads for each pull
+func (ldm *LayerDownloadManager) SetConcurrency(concurrency int) {
+	ldm.tm.SetConcurrency(concurrency)
+}
+
+// SetRegistryConcurrency sets the max concurrent downloads from each of the
+// registries in limits, by registry host. The downloads from these
+// registries don't count in the concurrency limit of the other downloads, so
+// that a slow registry doesn't hold them. The registries left out of limits
+// go back to the shared limit.
+func (ldm *LayerDownloadManager) SetRegistryConcurrency(limits map[string]int) {
+	ldm.mu.Lock()
+	defer ldm.mu.Unlock()
+	for host := range ldm.registryTMs {
+		if _, ok := limits[host]; !ok {
+			// The downloads in progress complete with the previous limit
+			delete(ldm.registryTMs, host)
+		}
+	}
+	for host, concurrency := range limits {
+		if tm, ok := ldm.registryTMs[host]; ok {
+			tm.SetConcurrency(concurrency)
+		} else {
+			ldm.registryTMs[host] = NewTransferManager(concurrency)
+		}
+	}
+}
+
+// transferManager returns the transfer manager to download the layer with,
+// the one of its registry if the registry has its own concurrency limit.
+func (ldm *LayerDownloadManager) transferManager(descriptor DownloadDescriptor) TransferManager {
+	rd, ok := descriptor.(DownloadDescriptorWithRegistry)
+	if !ok {
+		return ldm.tm
+	}
+	ldm.mu.Lock()
+	defer ldm.mu.Unlock()
+	if tm, ok := ldm.registryTMs[rd.Registry()]; ok {
+		return tm
+	}
+	return ldm.tm
+}
+
 type downloadTransfer struct {
 	Transfer
 
@@ -75,6 +125,16 @@ type DownloadDescriptorWithRegistered interface {
 	Registered(diffID layer.DiffID)
 }
 
+// DownloadDescriptorWithRegistry is a DownloadDescriptor that has an
+// additional Registry method, which returns the host of the registry the
+// layer is downloaded from. This allows the download manager to apply the
+// concurrency limit of the registry. This method is called if a cast to
+// DownloadDescriptorWithRegistry is successful.
+type DownloadDescriptorWithRegistry interface {
+	DownloadDescriptor
+	Registry() string
+}
+
 // Download is a blocking function which ensures the requested layers are
 // present in the layer store. It uses the string returned by the Key method to
 // deduplicate downloads. If a given layer is not already known to present in
@@ -125,7 +185,7 @@ func (ldm *LayerDownloadManager) Download(ctx context.Context, initialRootFS ima
 		if existingDownload, ok := downloadsByKey[key]; ok {
 			xferFunc := ldm.makeDownloadFuncFromDownload(descriptor, existingDownload, topDownload)
 			defer topDownload.Transfer.Release(watcher)
-			topDownloadUncasted, watcher = ldm.tm.Transfer(transferKey, xferFunc, progressOutput)
+			topDownloadUncasted, watcher = ldm.transferManager(descriptor).Transfer(transferKey, xferFunc, progressOutput)
 			topDownload = topDownloadUncasted.(*downloadTransfer)
 			continue
 		}
@@ -140,7 +200,7 @@ func (ldm *LayerDownloadManager) Download(ctx context.Context, initialRootFS ima
 		} else {
 			xferFunc = ldm.makeDownloadFunc(descriptor, rootFS.ChainID(), nil)
 		}
-		topDownloadUncasted, watcher = ldm.tm.Transfer(transferKey, xferFunc, progressOutput)
+		topDownloadUncasted, watcher = ldm.transferManager(descriptor).Transfer(transferKey, xferFunc, progressOutput)
 		topDownload = topDownloadUncasted.(*downloadTransfer)
 		downloadsByKey[key] = topDownload
 	}
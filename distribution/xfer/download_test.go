@@ -30,6 +30,10 @@ func (ml *mockLayer) TarStream() (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewBuffer(ml.layerData.Bytes())), nil
 }
 
+func (ml *mockLayer) TarStreamFrom(layer.ChainID) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (ml *mockLayer) ChainID() layer.ChainID {
 	return ml.chainID
 }
@@ -338,3 +342,67 @@ func TestCancelledDownload(t *testing.T) {
 	close(progressChan)
 	<-progressDone
 }
+
+// mockRegistryDescriptor is a mockDownloadDescriptor downloaded from a
+// registry, which records the maximum number of concurrent downloads from
+// the registry.
+type mockRegistryDescriptor struct {
+	*mockDownloadDescriptor
+	registry         string
+	currentDownloads *int32
+	maxDownloads     *int32
+}
+
+func (d *mockRegistryDescriptor) Registry() string {
+	return d.registry
+}
+
+func (d *mockRegistryDescriptor) Download(ctx context.Context, progressOutput progress.Output) (io.ReadCloser, int64, error) {
+	defer atomic.AddInt32(d.currentDownloads, -1)
+	current := atomic.AddInt32(d.currentDownloads, 1)
+	for {
+		max := atomic.LoadInt32(d.maxDownloads)
+		if current <= max || atomic.CompareAndSwapInt32(d.maxDownloads, max, current) {
+			break
+		}
+	}
+	return d.mockDownloadDescriptor.Download(ctx, progressOutput)
+}
+
+func TestRegistryConcurrencyLimit(t *testing.T) {
+	ldm := NewLayerDownloadManager(&mockLayerStore{make(map[layer.ChainID]*mockLayer)}, maxDownloadConcurrency)
+	ldm.SetRegistryConcurrency(map[string]int{"mirror.example.com": 1})
+
+	progressChan := make(chan progress.Progress)
+	progressDone := make(chan struct{})
+
+	go func() {
+		for range progressChan {
+		}
+		close(progressDone)
+	}()
+
+	var currentDownloads, maxDownloads int32
+	var descriptors []DownloadDescriptor
+	for _, d := range downloadDescriptors(nil) {
+		descriptors = append(descriptors, &mockRegistryDescriptor{
+			mockDownloadDescriptor: d.(*mockDownloadDescriptor),
+			registry:               "mirror.example.com",
+			currentDownloads:       &currentDownloads,
+			maxDownloads:           &maxDownloads,
+		})
+	}
+
+	_, releaseFunc, err := ldm.Download(context.Background(), *image.NewRootFS(), descriptors, progress.ChanOutput(progressChan))
+	if err != nil {
+		t.Fatalf("download error: %v", err)
+	}
+	releaseFunc()
+
+	close(progressChan)
+	<-progressDone
+
+	if maxDownloads != 1 {
+		t.Fatalf("expected the layers to be downloaded one at a time from the registry, got %d concurrent downloads", maxDownloads)
+	}
+}
@@ -0,0 +1,134 @@
+package xfer
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+)
+
+// partialGCInterval is the minimum interval between two garbage collections
+// of the partial blobs.
+const partialGCInterval = time.Hour
+
+// ErrPartialBlobInUse is returned when opening the partial download of a
+// blob which is already being downloaded.
+var ErrPartialBlobInUse = errors.New("the blob is already being downloaded")
+
+// PartialBlobStore keeps the partially downloaded blobs on disk by digest,
+// so that a download interrupted by an error is resumed by the next pull of
+// the blob instead of starting over. The partial blobs which haven't been
+// resumed for longer than a maximum age are garbage-collected.
+type PartialBlobStore struct {
+	root   string
+	maxAge time.Duration
+
+	mu     sync.Mutex
+	inUse  map[digest.Digest]struct{}
+	lastGC time.Time
+}
+
+// NewPartialBlobStore returns a PartialBlobStore keeping the partial blobs
+// in root, for at most maxAge since they were last written to. The stale
+// partial blobs left by a previous run are removed.
+func NewPartialBlobStore(root string, maxAge time.Duration) (*PartialBlobStore, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	s := &PartialBlobStore{
+		root:   root,
+		maxAge: maxAge,
+		inUse:  make(map[digest.Digest]struct{}),
+	}
+	s.GC()
+	return s, nil
+}
+
+func (s *PartialBlobStore) path(dgst digest.Digest) string {
+	return filepath.Join(s.root, string(dgst.Algorithm())+"-"+dgst.Hex())
+}
+
+// Open opens the partial download of the blob, to append the rest of the
+// blob to it, or creates it if there is none. ErrPartialBlobInUse is
+// returned if the blob is already being downloaded. The file must be
+// released with Release.
+func (s *PartialBlobStore) Open(dgst digest.Digest) (*os.File, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inUse[dgst]; ok {
+		return nil, ErrPartialBlobInUse
+	}
+	if time.Since(s.lastGC) > partialGCInterval {
+		s.gc()
+	}
+
+	f, err := os.OpenFile(s.path(dgst), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.inUse[dgst] = struct{}{}
+	return f, nil
+}
+
+// Release closes the partial download of the blob. It's removed if the
+// download is complete or can't be resumed, and kept to be resumed
+// otherwise.
+func (s *PartialBlobStore) Release(dgst digest.Digest, f *os.File, remove bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inUse, dgst)
+
+	err := f.Close()
+	if remove {
+		if rerr := os.Remove(f.Name()); rerr != nil && !os.IsNotExist(rerr) && err == nil {
+			err = rerr
+		}
+		return err
+	}
+	// The age of the partial blob starts over from the last attempt
+	now := time.Now()
+	if terr := os.Chtimes(f.Name(), now, now); terr != nil && err == nil {
+		err = terr
+	}
+	return err
+}
+
+// GC removes the partial blobs which haven't been written to for longer
+// than the maximum age, other than the ones being downloaded.
+func (s *PartialBlobStore) GC() {
+	s.mu.Lock()
+	s.gc()
+	s.mu.Unlock()
+}
+
+func (s *PartialBlobStore) gc() {
+	s.lastGC = time.Now()
+	files, err := ioutil.ReadDir(s.root)
+	if err != nil {
+		logrus.Errorf("Failed to list the partial blobs: %v", err)
+		return
+	}
+	inUse := make(map[string]struct{}, len(s.inUse))
+	for dgst := range s.inUse {
+		inUse[s.path(dgst)] = struct{}{}
+	}
+	for _, fi := range files {
+		pth := filepath.Join(s.root, fi.Name())
+		if _, ok := inUse[pth]; ok || time.Since(fi.ModTime()) <= s.maxAge {
+			continue
+		}
+		logrus.Debugf("Removing stale partial blob %s", fi.Name())
+		if err := os.RemoveAll(pth); err != nil {
+			logrus.Errorf("Failed to remove the partial blob %s: %v", fi.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package xfer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/digest"
+)
+
+func TestPartialBlobStore(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "partial-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	s, err := NewPartialBlobStore(tmp, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dgst := digest.FromBytes([]byte("blob"))
+
+	f, err := s.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(dgst); err != ErrPartialBlobInUse {
+		t.Fatalf("expected the blob to be in use, got %v", err)
+	}
+	if _, err := f.Write([]byte("bl")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Release(dgst, f, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The download is resumed from the partial blob
+	f, err = s.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset, err := f.Seek(0, os.SEEK_END); err != nil || offset != 2 {
+		t.Fatalf("expected to resume the download from 2 bytes, got %d (%v)", offset, err)
+	}
+	pth := f.Name()
+	if err := s.Release(dgst, f, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(pth); !os.IsNotExist(err) {
+		t.Fatalf("expected the complete blob to be removed, got %v", err)
+	}
+
+	if _, err := s.Open("sha256:invalid"); err == nil {
+		t.Fatal("expected an error opening an invalid digest")
+	}
+}
+
+func TestPartialBlobStoreGC(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "partial-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	s, err := NewPartialBlobStore(tmp, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, fresh, inUse := digest.FromBytes([]byte("stale")), digest.FromBytes([]byte("fresh")), digest.FromBytes([]byte("in use"))
+	for _, dgst := range []digest.Digest{stale, fresh} {
+		f, err := s.Open(dgst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Release(dgst, f, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := s.Open(inUse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Release(inUse, f, true)
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, dgst := range []digest.Digest{stale, inUse} {
+		if err := os.Chtimes(s.path(dgst), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.GC()
+
+	if _, err := os.Stat(s.path(stale)); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale partial blob to be removed, got %v", err)
+	}
+	for _, dgst := range []digest.Digest{fresh, inUse} {
+		if _, err := os.Stat(s.path(dgst)); err != nil {
+			t.Fatalf("expected the partial blob %s to be kept, got %v", dgst, err)
+		}
+	}
+}
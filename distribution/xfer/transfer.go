@@ -279,6 +279,10 @@ type TransferManager interface {
 	// so, it returns progress and error output from that transfer.
 	// Otherwise, it will call xferFunc to initiate the transfer.
 	Transfer(key string, xferFunc DoFunc, progressOutput progress.Output) (Transfer, *Watcher)
+	// SetConcurrency changes the maximum number of transfers that can
+	// run at the same time. Waiting transfers are started if the limit
+	// is raised; running transfers are not interrupted if it is lowered.
+	SetConcurrency(concurrency int)
 }
 
 type transferManager struct {
@@ -298,6 +302,19 @@ func NewTransferManager(concurrencyLimit int) TransferManager {
 	}
 }
 
+// SetConcurrency sets the concurrencyLimit
+func (tm *transferManager) SetConcurrency(concurrency int) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.concurrencyLimit = concurrency
+	for tm.activeTransfers < tm.concurrencyLimit && len(tm.waitingTransfers) != 0 {
+		close(tm.waitingTransfers[0])
+		tm.waitingTransfers = tm.waitingTransfers[1:]
+		tm.activeTransfers++
+	}
+}
+
 // Transfer checks if a transfer matching the given key is in progress. If not,
 // it starts one by calling xferFunc. The caller supplies a channel which
 // receives progress output from the transfer.
@@ -380,8 +397,9 @@ func (tm *transferManager) inactivate(start chan struct{}) {
 	// count.
 	select {
 	case <-start:
-		// Start next transfer if any are waiting
-		if len(tm.waitingTransfers) != 0 {
+		// Start next transfer if any are waiting, unless the
+		// concurrency limit was lowered below the running transfers.
+		if tm.activeTransfers <= tm.concurrencyLimit && len(tm.waitingTransfers) != 0 {
 			close(tm.waitingTransfers[0])
 			tm.waitingTransfers = tm.waitingTransfers[1:]
 		} else {
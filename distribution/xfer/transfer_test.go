@@ -125,6 +125,61 @@ func TestConcurrencyLimit(t *testing.T) {
 	}
 }
 
+func TestSetConcurrency(t *testing.T) {
+	var runningJobs int32
+	release := make(chan struct{})
+
+	makeXferFunc := func(id string) DoFunc {
+		return func(progressChan chan<- progress.Progress, start <-chan struct{}, inactive chan<- struct{}) Transfer {
+			xfer := NewTransfer()
+			go func() {
+				<-start
+				atomic.AddInt32(&runningJobs, 1)
+				<-release
+				atomic.AddInt32(&runningJobs, -1)
+				close(progressChan)
+			}()
+			return xfer
+		}
+	}
+
+	waitForRunningJobs := func(expected int32) {
+		for i := 0; i < 100; i++ {
+			if atomic.LoadInt32(&runningJobs) == expected {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("expected %d running jobs, got %d", expected, atomic.LoadInt32(&runningJobs))
+	}
+
+	tm := NewTransferManager(1)
+	progressChan := make(chan progress.Progress)
+	go func() {
+		for range progressChan {
+		}
+	}()
+
+	ids := []string{"id1", "id2", "id3", "id4"}
+	xfers := make([]Transfer, len(ids))
+	watchers := make([]*Watcher, len(ids))
+	for i, id := range ids {
+		xfers[i], watchers[i] = tm.Transfer(id, makeXferFunc(id), progress.ChanOutput(progressChan))
+	}
+	waitForRunningJobs(1)
+
+	// Raising the limit starts the waiting transfers
+	tm.SetConcurrency(3)
+	waitForRunningJobs(3)
+
+	close(release)
+	for i, xfer := range xfers {
+		<-xfer.Done()
+		xfer.Release(watchers[i])
+	}
+	close(progressChan)
+}
+
 func TestInactiveJobs(t *testing.T) {
 	concurrencyLimit := 3
 	var runningJobs int32
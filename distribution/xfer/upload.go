@@ -26,6 +26,11 @@ func NewLayerUploadManager(concurrencyLimit int) *LayerUploadManager {
 	}
 }
 
+// SetConcurrency sets the max concurrent uploads for each push
+func (lum *LayerUploadManager) SetConcurrency(concurrency int) {
+	lum.tm.SetConcurrency(concurrency)
+}
+
 type uploadTransfer struct {
 	Transfer
 
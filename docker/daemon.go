@@ -59,6 +59,8 @@ var (
 type DaemonCli struct {
 	*daemon.Config
 	flags *flag.FlagSet
+
+	authzMiddleware *authorization.Middleware // authzMiddleware enables to dynamically reload the authorization plugins
 }
 
 func presentInHelp(usage string) string { return usage }
@@ -72,6 +74,7 @@ func NewDaemonCli() *DaemonCli {
 	daemonConfig := new(daemon.Config)
 	daemonConfig.LogConfig.Config = make(map[string]string)
 	daemonConfig.ClusterOpts = make(map[string]string)
+	daemonConfig.RegistryMaxConcurrentDownloads = make(map[string]string)
 
 	if runtime.GOOS != "linux" {
 		daemonConfig.V2Only = true
@@ -313,6 +316,9 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 			logrus.Errorf("Error reconfiguring the daemon: %v", err)
 			return
 		}
+		if config.IsValueSet("authorization-plugins") {
+			cli.authzMiddleware.SetPlugins(authorization.NewPlugins(config.AuthorizationPlugins))
+		}
 		if config.IsValueSet("debug") {
 			debugEnabled := utils.IsDebugEnabled()
 			switch {
@@ -453,9 +459,8 @@ func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config
 	u := middleware.NewUserAgentMiddleware(v)
 	s.UseMiddleware(u)
 
-	if len(cli.Config.AuthorizationPlugins) > 0 {
-		authZPlugins := authorization.NewPlugins(cli.Config.AuthorizationPlugins)
-		handleAuthorization := authorization.NewMiddleware(authZPlugins)
-		s.UseMiddleware(handleAuthorization)
-	}
+	// The middleware is always installed, so that authorization plugins
+	// can be enabled by reloading the configuration.
+	cli.authzMiddleware = authorization.NewMiddleware(authorization.NewPlugins(cli.Config.AuthorizationPlugins))
+	s.UseMiddleware(cli.authzMiddleware)
 }
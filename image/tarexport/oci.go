@@ -0,0 +1,379 @@
+package tarexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/reference"
+)
+
+const (
+	ociLayoutFileName = "oci-layout"
+	ociIndexFileName  = "index.json"
+	ociBlobsDirName   = "blobs"
+	ociLayoutVersion  = "1.0.0"
+
+	ociMediaTypeIndex    = "application/vnd.oci.image.index.v1+json"
+	ociMediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeConfig   = "application/vnd.oci.image.config.v1+json"
+	ociMediaTypeLayer    = "application/vnd.oci.image.layer.v1.tar"
+
+	// ociRefNameAnnotation is the annotation of the index entries naming
+	// the image they point to.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+type ociLayout struct {
+	Version string `json:"imageLayoutVersion"`
+}
+
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *ociPlatform      `json:"platform,omitempty"`
+}
+
+// ociIndex is the index.json of the layout, or a nested image index.
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+type ociSaveSession struct {
+	*tarexporter
+	outDir      string
+	savedLayers map[layer.ChainID]ociDescriptor
+}
+
+// SaveOCI writes the images as an OCI image layout: the image
+// configurations, manifests and uncompressed layers are stored by digest in
+// the blobs directory, and index.json lists the manifests of the images,
+// once for each of their tags.
+func (l *tarexporter) SaveOCI(names []string, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s := &ociSaveSession{
+		tarexporter: l,
+		outDir:      tempDir,
+		savedLayers: make(map[layer.ChainID]ociDescriptor),
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, ociBlobsDirName, string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+
+	index := ociIndex{SchemaVersion: 2, MediaType: ociMediaTypeIndex, Manifests: []ociDescriptor{}}
+	for id, imageDescr := range images {
+		manifest, err := s.saveImage(id)
+		if err != nil {
+			return err
+		}
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, manifest)
+		}
+		for _, ref := range imageDescr.refs {
+			tagged := manifest
+			tagged.Annotations = map[string]string{ociRefNameAnnotation: ref.String()}
+			index.Manifests = append(index.Manifests, tagged)
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(tempDir, ociIndexFileName), index); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(tempDir, ociLayoutFileName), ociLayout{Version: ociLayoutVersion}); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveImage writes the configuration, layers and manifest of the image to
+// the blobs, and returns the descriptor of the manifest.
+func (s *ociSaveSession) saveImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	manifest := ociManifest{SchemaVersion: 2, MediaType: ociMediaTypeManifest}
+	rootFS := *img.RootFS
+	for i := range img.RootFS.DiffIDs {
+		rootFS.DiffIDs = img.RootFS.DiffIDs[:i+1]
+		layerDescr, err := s.saveLayer(rootFS.ChainID())
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, layerDescr)
+	}
+
+	// The image ID is the digest of its configuration
+	manifest.Config, err = s.writeBlob(ociMediaTypeConfig, bytes.NewReader(img.RawJSON()))
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	if manifest.Config.Digest != digest.Digest(id) {
+		return ociDescriptor{}, fmt.Errorf("invalid configuration for image %s: digest %s", id, manifest.Config.Digest)
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	return s.writeBlob(ociMediaTypeManifest, bytes.NewReader(manifestJSON))
+}
+
+func (s *ociSaveSession) saveLayer(id layer.ChainID) (ociDescriptor, error) {
+	if descr, exists := s.savedLayers[id]; exists {
+		return descr, nil
+	}
+
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	descr, err := s.writeBlob(ociMediaTypeLayer, arch)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	if descr.Digest != digest.Digest(l.DiffID()) {
+		return ociDescriptor{}, fmt.Errorf("invalid layer %s: digest %s", l.DiffID(), descr.Digest)
+	}
+	s.savedLayers[id] = descr
+	return descr, nil
+}
+
+// writeBlob writes the content of r to the blobs, named by its digest.
+func (s *ociSaveSession) writeBlob(mediaType string, r io.Reader) (ociDescriptor, error) {
+	blobsDir := filepath.Join(s.outDir, ociBlobsDirName, string(digest.Canonical))
+	f, err := ioutil.TempFile(blobsDir, ".tmp-")
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer os.Remove(f.Name())
+
+	digester := digest.Canonical.New()
+	size, err := io.Copy(io.MultiWriter(f, digester.Hash()), r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	dgst := digester.Digest()
+	if err := os.Rename(f.Name(), filepath.Join(blobsDir, dgst.Hex())); err != nil {
+		return ociDescriptor{}, err
+	}
+	return ociDescriptor{MediaType: mediaType, Digest: dgst, Size: size}, nil
+}
+
+func writeJSONFile(pth string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(pth, b, 0644)
+}
+
+// ociLoad loads the images of an OCI image layout. The images are tagged
+// with the reference in the annotation of their index entry, when it names
+// a tagged image.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ociLayout
+	if err := readJSONFile(tmpDir, ociLayoutFileName, &layout); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(layout.Version, "1.") {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+
+	var index ociIndex
+	if err := readJSONFile(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	for _, descr := range index.Manifests {
+		imgID, err := l.ociLoadImage(tmpDir, descr, progressOutput)
+		if err != nil {
+			return err
+		}
+
+		if named, err := reference.ParseNamed(descr.Annotations[ociRefNameAnnotation]); err == nil {
+			if ref, ok := named.(reference.NamedTagged); ok {
+				if err := l.setLoadedTag(ref, imgID, outStream); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		fmt.Fprintf(outStream, "Loaded image ID: %s\n", imgID)
+	}
+	return nil
+}
+
+// ociLoadImage loads the image of an index entry, which is either a
+// manifest or a nested index. The manifest of the current platform is
+// loaded from a nested index.
+func (l *tarexporter) ociLoadImage(tmpDir string, descr ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	switch descr.MediaType {
+	case ociMediaTypeIndex, manifestlist.MediaTypeManifestList:
+		var index ociIndex
+		if err := readBlob(tmpDir, descr.Digest, &index); err != nil {
+			return "", err
+		}
+		for _, m := range index.Manifests {
+			if m.Platform != nil && m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH {
+				return l.ociLoadImage(tmpDir, m, progressOutput)
+			}
+		}
+		return "", fmt.Errorf("no image found for %s/%s in index %s", runtime.GOOS, runtime.GOARCH, descr.Digest)
+	case ociMediaTypeManifest, schema2.MediaTypeManifest:
+	default:
+		return "", fmt.Errorf("unsupported media type %q for %s", descr.MediaType, descr.Digest)
+	}
+
+	var manifest ociManifest
+	if err := readBlob(tmpDir, descr.Digest, &manifest); err != nil {
+		return "", err
+	}
+	configPath, err := blobPath(tmpDir, manifest.Config.Digest)
+	if err != nil {
+		return "", err
+	}
+	config, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return "", err
+	}
+	if manifest.Config.Digest.Algorithm().FromBytes(config) != manifest.Config.Digest {
+		return "", fmt.Errorf("invalid configuration %s: digest mismatch", manifest.Config.Digest)
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	if img.RootFS == nil {
+		return "", fmt.Errorf("invalid configuration %s: no rootfs", manifest.Config.Digest)
+	}
+
+	if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for i, diffID := range img.RootFS.DiffIDs {
+		layerPath, err := blobPath(tmpDir, manifest.Layers[i].Digest)
+		if err != nil {
+			return "", err
+		}
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.ls.Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.ls, newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+// blobPath returns the path of a blob of the layout.
+func blobPath(tmpDir string, dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(tmpDir, filepath.Join(ociBlobsDirName, string(dgst.Algorithm()), dgst.Hex()))
+}
+
+// readBlob decodes a JSON blob of the layout, after verifying its digest.
+func readBlob(tmpDir string, dgst digest.Digest, v interface{}) error {
+	pth, err := blobPath(tmpDir, dgst)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return err
+	}
+	if dgst.Algorithm().FromBytes(b) != dgst {
+		return fmt.Errorf("invalid blob %s: digest mismatch", dgst)
+	}
+	return json.Unmarshal(b, v)
+}
+
+func readJSONFile(tmpDir, name string, v interface{}) error {
+	pth, err := safePath(tmpDir, name)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
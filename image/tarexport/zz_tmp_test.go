@@ -0,0 +1,83 @@
+package tarexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/daemon/graphdriver"
+	_ "github.com/docker/docker/daemon/graphdriver/vfs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/reference"
+)
+
+func stores(t *testing.T, root string) *tarexporter {
+	graphdriver.ApplyUncompressedLayer = archive.UnpackLayer
+	ls, err := layer.NewStoreFromOptions(layer.StoreOptions{StorePath: root, MetadataStorePathTemplate: filepath.Join(root, "image", "%s", "layerdb"), GraphDriver: "vfs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, "imagedb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := image.NewImageStore(fs, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := reference.NewReferenceStore(filepath.Join(root, "repositories.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tarexporter{is: is, ls: ls, rs: rs}
+}
+
+func TestTmpOCI(t *testing.T) {
+	tmp, _ := ioutil.TempDir("", "oci")
+	defer os.RemoveAll(tmp)
+	src := filepath.Join(tmp, "src")
+	os.MkdirAll(filepath.Join(src, "a"), 0755)
+	ioutil.WriteFile(filepath.Join(src, "a", "f"), []byte("hello"), 0644)
+	tr, _ := archive.Tar(src, archive.Uncompressed)
+
+	e := stores(t, filepath.Join(tmp, "s1"))
+	l, err := e.ls.Register(tr, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootfs := image.NewRootFS()
+	rootfs.Append(l.DiffID())
+	cfg, _ := json.Marshal(map[string]interface{}{"architecture": "amd64", "os": "linux", "rootfs": rootfs, "config": map[string]interface{}{}})
+	id, err := e.is.Create(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	named, _ := reference.ParseNamed("foo/bar:v1")
+	e.rs.AddTag(named.(reference.NamedTagged), id, true)
+
+	var buf bytes.Buffer
+	if err := e.SaveOCI([]string{"foo/bar:v1"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(tmp, "out")
+	archive.Untar(bytes.NewReader(buf.Bytes()), out, nil)
+	filepath.Walk(out, func(p string, fi os.FileInfo, err error) error { t.Log(p, fi.Size()); return nil })
+	b, _ := ioutil.ReadFile(filepath.Join(out, "index.json"))
+	t.Log(string(b))
+
+	e2 := stores(t, filepath.Join(tmp, "s2"))
+	var o bytes.Buffer
+	if err := e2.Load(ioutil.NopCloser(bytes.NewReader(buf.Bytes())), &o, true); err != nil {
+		t.Fatal(err)
+	}
+	got, err := e2.rs.Get(named)
+	if err != nil || got != id {
+		t.Fatal(got, err)
+	}
+	t.Log(o.String())
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/docker/engine-api/types"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestSystemDfApi(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "run", "--name", "dfcontainer", "-v", "dfvol:/data", "busybox", "sh", "-c", "echo hello > /data/file")
+
+	status, body, err := sockRequest("GET", "/system/df", nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusOK)
+
+	var du types.DiskUsage
+	c.Assert(json.Unmarshal(body, &du), checker.IsNil)
+	c.Assert(du.LayersSize, checker.GreaterThan, int64(0))
+
+	var busybox *types.Image
+	for _, i := range du.Images {
+		for _, t := range i.RepoTags {
+			if t == "busybox:latest" {
+				busybox = i
+			}
+		}
+	}
+	c.Assert(busybox, checker.NotNil)
+	c.Assert(busybox.Containers, checker.Equals, int64(1))
+
+	var vol *types.Volume
+	for _, v := range du.Volumes {
+		if v.Name == "dfvol" {
+			vol = v
+		}
+	}
+	c.Assert(vol, checker.NotNil)
+	c.Assert(vol.UsageData, checker.NotNil)
+	c.Assert(vol.UsageData.RefCount, checker.Equals, 1)
+	c.Assert(vol.UsageData.Size, checker.Equals, int64(len("hello\n")))
+}
@@ -6883,3 +6883,131 @@ func (s *DockerRegistryAuthHtpasswdSuite) TestBuildWithExternalAuth(c *check.C)
 	out, _, err := runCommandWithOutput(buildCmd)
 	c.Assert(err, check.IsNil, check.Commentf(out))
 }
+
+func (s *DockerSuite) TestBuildMultiStageCopyFrom(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildmultistage"
+
+	ctx, err := fakeContext(`FROM busybox AS build
+		COPY foo /build/foo
+		RUN echo built > /build/bar
+		ENV STAGE=build
+
+		FROM busybox
+		RUN echo tools > /tools
+
+		FROM busybox
+		COPY --from=build /build/ /app/
+		COPY --from=1 /tools /tools
+		COPY --from=busybox /bin/busybox /copied-busybox
+		RUN cat /app/foo /app/bar /tools`,
+		map[string]string{
+			"foo": "foo\n",
+		})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut(name, ctx, true)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "foo\nbuilt\ntools\n")
+
+	// Nothing from the earlier stages should have leaked into the final image
+	env := inspectFieldJSON(c, name, "Config.Env")
+	c.Assert(env, checker.Not(checker.Contains), "STAGE=build")
+
+	// Building again is fully cached
+	_, out, err = buildImageFromContextWithOut(name, ctx, true)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.Count(out, "Using cache"), checker.Equals, 8)
+}
+
+func (s *DockerSuite) TestBuildMultiStageInvalidFrom(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	_, out, err := buildImageWithOut("testbuildmultistageinvalid",
+		`FROM busybox AS first
+		FROM busybox AS first`, true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, `duplicate name for build stage: "first"`)
+
+	_, out, err = buildImageWithOut("testbuildmultistageinvalid",
+		`FROM busybox AS self
+		COPY --from=self /bin/sh /sh`, true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "self refers to the current build stage")
+}
+
+func (s *DockerSuite) TestBuildSquash(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildsquash"
+
+	_, err := buildImage(name,
+		`FROM busybox
+		RUN dd if=/dev/zero of=/file bs=1M count=10
+		RUN echo hello > /hello
+		RUN rm /file
+		CMD ["cat", "/hello"]`,
+		true, "--squash")
+	c.Assert(err, checker.IsNil)
+
+	out, _ := dockerCmd(c, "run", "--rm", name)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello")
+
+	// The squashed image has a single layer on top of busybox
+	baseLayers := inspectFieldJSON(c, "busybox", "RootFS.Layers")
+	var base, squashed []string
+	c.Assert(json.Unmarshal([]byte(baseLayers), &base), checker.IsNil)
+	c.Assert(json.Unmarshal([]byte(inspectFieldJSON(c, name, "RootFS.Layers")), &squashed), checker.IsNil)
+	c.Assert(squashed, checker.HasLen, len(base)+1)
+
+	// The removed file takes no space in the squashed layer
+	size, err := strconv.Atoi(inspectField(c, name, "Size"))
+	c.Assert(err, checker.IsNil)
+	baseSize, err := strconv.Atoi(inspectField(c, "busybox", "Size"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(size-baseSize < 1024*1024, checker.True, check.Commentf("squashed image is %d bytes larger than busybox", size-baseSize))
+
+	// Every instruction is still in the history
+	out, _ = dockerCmd(c, "history", "--no-trunc", name)
+	c.Assert(out, checker.Contains, "dd if=/dev/zero")
+	c.Assert(out, checker.Contains, "rm /file")
+}
+
+func (s *DockerSuite) TestBuildShell(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildshell"
+
+	_, err := buildImage(name,
+		`FROM busybox
+		SHELL ["/bin/sh", "-xc"]
+		RUN [ "$(echo shell)" = "shell" ]
+		CMD echo cmd`,
+		true)
+	c.Assert(err, checker.IsNil)
+
+	res := inspectFieldJSON(c, name, "Config.Shell")
+	c.Assert(res, checker.Equals, `["/bin/sh","-xc"]`)
+	res = inspectFieldJSON(c, name, "Config.Cmd")
+	c.Assert(res, checker.Equals, `["/bin/sh","-xc","echo cmd"]`)
+
+	// The shell is inherited by child images and their ONBUILD triggers
+	_, err = buildImage(name+"-onbuild",
+		`FROM busybox
+		SHELL ["/bin/sh", "-xc"]
+		ONBUILD ENTRYPOINT echo entrypoint`,
+		true)
+	c.Assert(err, checker.IsNil)
+	_, err = buildImage(name+"-child", "FROM "+name+"-onbuild", true)
+	c.Assert(err, checker.IsNil)
+	res = inspectFieldJSON(c, name+"-child", "Config.Entrypoint")
+	c.Assert(res, checker.Equals, `["/bin/sh","-xc","echo entrypoint"]`)
+}
+
+func (s *DockerSuite) TestBuildShellNotJSON(c *check.C) {
+	_, out, err := buildImageWithOut("testbuildshellnotjson",
+		`FROM busybox
+		SHELL /bin/sh -c`,
+		true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "SHELL requires the arguments to be in JSON form")
+}
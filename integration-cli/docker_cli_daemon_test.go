@@ -1244,7 +1244,7 @@ func (s *DockerDaemonSuite) TestDaemonLoggingDriverNoneLogsError(c *check.C) {
 
 	out, err = s.d.Cmd("logs", "test")
 	c.Assert(err, check.NotNil, check.Commentf("Logs should fail with 'none' driver"))
-	expected := `"logs" command is supported only for "json-file" and "journald" logging drivers (got: none)`
+	expected := `"logs" command is not supported for the "none" logging driver`
 	c.Assert(out, checker.Contains, expected)
 }
 
@@ -2221,3 +2221,95 @@ func (s *DockerSuite) TestDaemonDiscoveryBackendConfigReload(c *check.C) {
 	c.Assert(out, checker.Contains, fmt.Sprintf("Cluster Store: consul://consuladdr:consulport/some/path"))
 	c.Assert(out, checker.Contains, fmt.Sprintf("Cluster Advertise: 192.168.56.100:0"))
 }
+
+func (s *DockerDaemonSuite) TestDaemonEventsJournalSurvivesRestart(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	c.Assert(s.d.StartWithBusybox("--events-log-max-size=1m"), checker.IsNil)
+
+	since := time.Now().Unix()
+	out, err := s.d.Cmd("volume", "create", "--name", "journaled")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	until := time.Now().Unix() + 1
+
+	c.Assert(s.d.Restart("--events-log-max-size=1m"), checker.IsNil)
+
+	out, err = s.d.Cmd("events", "--since", strconv.FormatInt(since, 10), "--until", strconv.FormatInt(until, 10), "--filter", "type=volume")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "volume create journaled")
+}
+
+func (s *DockerDaemonSuite) TestDaemonEventsReloadStartShutdown(c *check.C) {
+	testRequires(c, SameHostDaemon, DaemonIsLinux)
+
+	configFile, err := ioutil.TempFile("", "daemon-events")
+	c.Assert(err, checker.IsNil)
+	configFilePath := configFile.Name()
+	defer os.Remove(configFilePath)
+	fmt.Fprintf(configFile, "%s", `{ "labels": ["foo=bar"] }`)
+	configFile.Close()
+
+	args := []string{"--log-level=info", "--events-log-max-size=1m", fmt.Sprintf("--config-file=%s", configFilePath)}
+	since := strconv.FormatInt(time.Now().Unix(), 10)
+	c.Assert(s.d.Start(args...), checker.IsNil)
+
+	c.Assert(ioutil.WriteFile(configFilePath, []byte(`{ "labels": ["foo=bar"], "debug": true }`), 0644), checker.IsNil)
+	c.Assert(syscall.Kill(s.d.cmd.Process.Pid, syscall.SIGHUP), checker.IsNil)
+	time.Sleep(3 * time.Second)
+
+	// The shutdown event is read back from the events journal
+	c.Assert(s.d.Restart(args...), checker.IsNil)
+
+	until := strconv.FormatInt(time.Now().Unix(), 10)
+	out, err := s.d.Cmd("events", "--since", since, "--until", until, "--filter", "type=daemon")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "daemon start")
+	c.Assert(out, checker.Contains, "daemon reload")
+	c.Assert(out, checker.Contains, "debug=true")
+	c.Assert(out, checker.Contains, "daemon shutdown")
+}
+
+func (s *DockerDaemonSuite) TestDaemonReloadRegistryAndLogConfig(c *check.C) {
+	testRequires(c, SameHostDaemon, DaemonIsLinux)
+
+	configFile, err := ioutil.TempFile("", "daemon-reload")
+	c.Assert(err, checker.IsNil)
+	configFilePath := configFile.Name()
+	defer os.Remove(configFilePath)
+	fmt.Fprintf(configFile, "%s", `{ "log-driver": "json-file" }`)
+	configFile.Close()
+
+	c.Assert(s.d.StartWithBusybox(fmt.Sprintf("--config-file=%s", configFilePath)), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "before", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	newConfig := `{ "log-driver": "none", "insecure-registries": ["myregistry:5000"], "max-concurrent-downloads": 1 }`
+	c.Assert(ioutil.WriteFile(configFilePath, []byte(newConfig), 0644), checker.IsNil)
+	c.Assert(syscall.Kill(s.d.cmd.Process.Pid, syscall.SIGHUP), checker.IsNil)
+	time.Sleep(3 * time.Second)
+
+	out, err = s.d.Cmd("info")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Logging Driver: none")
+	c.Assert(out, checker.Contains, "myregistry:5000")
+
+	// Running containers keep their log driver
+	out, err = s.d.Cmd("inspect", "--format", "{{.HostConfig.LogConfig.Type}}", "before")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "json-file")
+
+	out, err = s.d.Cmd("run", "-d", "--name", "after", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("inspect", "--format", "{{.HostConfig.LogConfig.Type}}", "after")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "none")
+
+	// An invalid configuration isn't applied at all
+	c.Assert(ioutil.WriteFile(configFilePath, []byte(`{ "log-driver": "json-file", "max-concurrent-uploads": 0 }`), 0644), checker.IsNil)
+	c.Assert(syscall.Kill(s.d.cmd.Process.Pid, syscall.SIGHUP), checker.IsNil)
+	time.Sleep(3 * time.Second)
+
+	out, err = s.d.Cmd("info")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Logging Driver: none")
+}
@@ -193,6 +193,28 @@ func (s *DockerSuite) TestLogsSince(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestLogsUntil(c *check.C) {
+	name := "testlogsuntil"
+	dockerCmd(c, "run", "--name="+name, "busybox", "/bin/sh", "-c", "for i in $(seq 1 3); do echo log$i; sleep 2; done")
+	out, _ := dockerCmd(c, "logs", "-t", name)
+
+	log2Line := strings.Split(strings.Split(out, "\n")[1], " ")
+	t, err := time.Parse(time.RFC3339Nano, log2Line[0]) // the timestamp log2 is written
+	c.Assert(err, checker.IsNil)
+	until := t.Format(time.RFC3339Nano)
+
+	// log3 is written 2s after log2
+	out, _ = dockerCmd(c, "logs", "--until="+until, name)
+	c.Assert(out, checker.Equals, "log1\nlog2\n", check.Commentf("unexpected logs returned, until=%v", until))
+
+	out, _ = dockerCmd(c, "logs", "--tail=1", "--until="+until, name)
+	c.Assert(out, checker.Equals, "log2\n", check.Commentf("unexpected logs returned, until=%v", until))
+
+	// The logs are not followed past until
+	out, _ = dockerCmd(c, "logs", "-f", "--until="+until, name)
+	c.Assert(out, checker.Equals, "log1\nlog2\n")
+}
+
 func (s *DockerSuite) TestLogsSinceFutureFollow(c *check.C) {
 	// TODO Windows TP5 - Figure out why this test is so flakey. Disabled for now.
 	testRequires(c, DaemonIsLinux)
@@ -307,3 +329,58 @@ func (s *DockerSuite) TestLogsCLIContainerNotFound(c *check.C) {
 	message := fmt.Sprintf("Error: No such container: %s\n", name)
 	c.Assert(out, checker.Equals, message)
 }
+
+func (s *DockerSuite) TestLogsNonBlockingMode(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	out, _ := dockerCmd(c, "run", "-d", "--log-opt", "mode=non-blocking", "--log-opt", "max-buffer-size=4m", "busybox", "sh", "-c", "echo hello")
+	id := strings.TrimSpace(out)
+	dockerCmd(c, "wait", id)
+
+	out, _ = dockerCmd(c, "logs", id)
+	c.Assert(out, checker.Equals, "hello\n")
+
+	out, _ = dockerCmd(c, "inspect", "--format", "{{.LogDroppedMessages}}", id)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "0")
+
+	out, _, err := dockerCmdWithError("run", "--log-opt", "mode=invalid", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "logging mode not supported: invalid")
+
+	out, _, err = dockerCmdWithError("run", "--log-opt", "max-buffer-size=4m", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "max-buffer-size option is only supported with 'mode=non-blocking'")
+}
+
+func (s *DockerSuite) TestLogsFromLocalCache(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	// gelf can't read the logs back, and sending them over UDP doesn't
+	// require a server
+	out, _ := dockerCmd(c, "run", "-d", "--log-driver=gelf", "--log-opt", "gelf-address=udp://127.0.0.1:12201", "busybox", "sh", "-c", "echo hello")
+	id := strings.TrimSpace(out)
+	dockerCmd(c, "wait", id)
+
+	out, _ = dockerCmd(c, "logs", id)
+	c.Assert(out, checker.Equals, "hello\n")
+
+	out, _ = dockerCmd(c, "run", "-d", "--log-driver=gelf", "--log-opt", "gelf-address=udp://127.0.0.1:12201", "--log-opt", "cache-disabled=true", "busybox", "sh", "-c", "echo hello")
+	id = strings.TrimSpace(out)
+	dockerCmd(c, "wait", id)
+
+	out, _, err := dockerCmdWithError("logs", id)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "does not support reading")
+}
+
+func (s *DockerSuite) TestLogsLocalDriver(c *check.C) {
+	out, _ := dockerCmd(c, "run", "-d", "--log-driver=local", "--log-opt", "max-size=1k", "--log-opt", "max-file=3", "busybox", "sh", "-c", "for i in $(seq 1 100); do echo line$i; done; echo error >&2")
+	id := strings.TrimSpace(out)
+	dockerCmd(c, "wait", id)
+
+	out, _ = dockerCmd(c, "logs", "--tail=3", id)
+	c.Assert(out, checker.Contains, "line99\nline100\n")
+	c.Assert(out, checker.Not(checker.Contains), "line98\n")
+
+	_, stderr, _, err := runCommandWithStdoutStderr(exec.Command(dockerBinary, "logs", "--tail=1", id))
+	c.Assert(err, checker.IsNil)
+	c.Assert(stderr, checker.Equals, "error\n")
+}
@@ -0,0 +1,90 @@
+// +build !windows
+
+package main
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestPruneContainer(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	out, _ := dockerCmd(c, "run", "-d", "busybox", "true")
+	stopped := strings.TrimSpace(out)
+	out, _ = runSleepingContainer(c, "-d")
+	running := strings.TrimSpace(out)
+	dockerCmd(c, "wait", stopped)
+
+	out, _ = dockerCmd(c, "container", "prune", "-f")
+	c.Assert(out, checker.Contains, stopped)
+	c.Assert(out, checker.Not(checker.Contains), running)
+	c.Assert(out, checker.Contains, "Total reclaimed space:")
+
+	out, _ = dockerCmd(c, "ps", "-aq", "--no-trunc")
+	c.Assert(out, checker.Not(checker.Contains), stopped)
+	c.Assert(out, checker.Contains, running)
+}
+
+func (s *DockerSuite) TestPruneContainerLabelFilter(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	out, _ := dockerCmd(c, "run", "-d", "--label", "prune=yes", "busybox", "true")
+	labeled := strings.TrimSpace(out)
+	out, _ = dockerCmd(c, "run", "-d", "busybox", "true")
+	unlabeled := strings.TrimSpace(out)
+	dockerCmd(c, "wait", labeled, unlabeled)
+
+	out, _ = dockerCmd(c, "container", "prune", "-f", "--filter", "label=prune=yes")
+	c.Assert(out, checker.Contains, labeled)
+	c.Assert(out, checker.Not(checker.Contains), unlabeled)
+}
+
+func (s *DockerSuite) TestPruneVolume(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "volume", "create", "--name", "unused")
+	dockerCmd(c, "volume", "create", "--name", "used")
+	dockerCmd(c, "create", "-v", "used:/data", "busybox")
+
+	out, _ := dockerCmd(c, "volume", "prune", "-f")
+	c.Assert(out, checker.Contains, "unused")
+
+	out, _ = dockerCmd(c, "volume", "ls", "-q")
+	c.Assert(out, checker.Not(checker.Contains), "unused")
+	c.Assert(out, checker.Contains, "used")
+}
+
+func (s *DockerSuite) TestPruneNetwork(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+	dockerCmd(c, "network", "create", "unused-net")
+	dockerCmd(c, "network", "create", "used-net")
+	runSleepingContainer(c, "-d", "--net", "used-net")
+
+	out, _ := dockerCmd(c, "network", "prune", "-f")
+	c.Assert(out, checker.Contains, "Deleted Networks:")
+
+	out, _ = dockerCmd(c, "network", "ls")
+	c.Assert(out, checker.Not(checker.Contains), "unused-net")
+	c.Assert(out, checker.Contains, "used-net")
+	c.Assert(out, checker.Contains, "bridge")
+}
+
+func (s *DockerSuite) TestPruneImage(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testpruneimage"
+	_, err := buildImage(name, "FROM busybox\nLABEL prune=yes", true)
+	c.Assert(err, checker.IsNil)
+	id, err := getIDByName(name)
+	c.Assert(err, checker.IsNil)
+
+	// A tagged image isn't dangling
+	out, _ := dockerCmd(c, "image", "prune", "-f")
+	c.Assert(out, checker.Not(checker.Contains), id)
+
+	out, _ = dockerCmd(c, "image", "prune", "-f", "-a", "--filter", "label=prune=yes")
+	c.Assert(out, checker.Contains, "untagged: "+name+":latest")
+	c.Assert(out, checker.Contains, "deleted: "+id)
+
+	out, _ = dockerCmd(c, "images", "-q", "--no-trunc", "busybox")
+	c.Assert(strings.TrimSpace(out), checker.Not(checker.Equals), "")
+}
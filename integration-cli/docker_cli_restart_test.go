@@ -120,6 +120,23 @@ func (s *DockerSuite) TestRestartContainerwithGoodContainer(c *check.C) {
 
 }
 
+func (s *DockerSuite) TestRestartPolicyOnUnhealthy(c *check.C) {
+	testRequires(c, DaemonIsLinux) // busybox doesn't work on Windows
+
+	out, _ := dockerCmd(c, "run", "-d", "--restart=on-unhealthy:2",
+		"--health-cmd=false", "--health-interval=1s", "busybox", "top")
+
+	id := strings.TrimSpace(out)
+	threshold := inspectField(c, id, "HostConfig.RestartPolicy.UnhealthyThreshold")
+	c.Assert(threshold, checker.Equals, "2")
+
+	err := waitInspect(id, "{{.RestartCount}}", "1", 30*time.Second)
+	c.Assert(err, checker.IsNil)
+
+	reason := inspectField(c, id, "LastRestartReason")
+	c.Assert(reason, checker.Contains, "consecutive failed health checks")
+}
+
 func (s *DockerSuite) TestRestartContainerSuccess(c *check.C) {
 	testRequires(c, SameHostDaemon)
 
@@ -350,3 +350,58 @@ func (s *DockerSuite) TestSaveLoadParents(c *check.C) {
 	inspectOut = inspectField(c, idFoo, "Parent")
 	c.Assert(inspectOut, checker.Equals, "")
 }
+
+func (s *DockerSuite) TestSaveLoadOCILayout(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	repoName := "foobar-save-load-oci"
+	dockerCmd(c, "tag", "busybox", repoName)
+	id := inspectField(c, repoName, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-load-oci")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	// The layout is extracted when it's saved to a directory
+	dockerCmd(c, "save", "--format", "oci", "-o", tmpDir, repoName)
+
+	layout, err := ioutil.ReadFile(filepath.Join(tmpDir, "oci-layout"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(layout), checker.Equals, `{"imageLayoutVersion":"1.0.0"}`)
+
+	var index struct {
+		Manifests []struct {
+			Digest      digest.Digest
+			Annotations map[string]string
+		}
+	}
+	b, err := ioutil.ReadFile(filepath.Join(tmpDir, "index.json"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(json.Unmarshal(b, &index), checker.IsNil)
+	c.Assert(index.Manifests, checker.HasLen, 1)
+	c.Assert(index.Manifests[0].Annotations["org.opencontainers.image.ref.name"], checker.Equals, repoName+":latest")
+
+	var manifest struct {
+		Config struct {
+			Digest digest.Digest
+		}
+	}
+	b, err = ioutil.ReadFile(filepath.Join(tmpDir, "blobs", "sha256", index.Manifests[0].Digest.Hex()))
+	c.Assert(err, checker.IsNil)
+	c.Assert(json.Unmarshal(b, &manifest), checker.IsNil)
+	c.Assert(string(manifest.Config.Digest), checker.Equals, id)
+
+	dockerCmd(c, "rmi", repoName)
+	dockerCmd(c, "load", "-i", tmpDir)
+	c.Assert(inspectField(c, repoName, "Id"), checker.Equals, id)
+
+	// The layout can be saved as a tar too
+	outfile := filepath.Join(tmpDir, "out.tar")
+	dockerCmd(c, "save", "--format", "oci", "-o", outfile, repoName)
+	dockerCmd(c, "rmi", repoName)
+	dockerCmd(c, "load", "-i", outfile)
+	c.Assert(inspectField(c, repoName, "Id"), checker.Equals, id)
+
+	out, _, err := dockerCmdWithError("save", "--format", "foo", "-o", outfile, repoName)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Invalid format")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestSystemDf(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "volume", "create", "--name", "dfvol")
+	dockerCmd(c, "run", "--name", "dfcontainer", "-v", "dfvol:/data", "busybox", "sh", "-c", "echo hello > /data/file && echo hello > /file")
+
+	out, _ := dockerCmd(c, "system", "df")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(lines, checker.HasLen, 4)
+	c.Assert(lines[0], checker.Contains, "RECLAIMABLE")
+	c.Assert(lines[1], checker.HasPrefix, "Images")
+	c.Assert(lines[2], checker.HasPrefix, "Containers")
+	c.Assert(lines[3], checker.HasPrefix, "Volumes")
+
+	out, _ = dockerCmd(c, "system", "df", "-v")
+	c.Assert(out, checker.Contains, "Images space usage:")
+	c.Assert(out, checker.Contains, "dfcontainer")
+	c.Assert(out, checker.Contains, "dfvol")
+}
@@ -361,6 +361,26 @@ func (clnt *client) Signal(containerID string, sig int) error {
 	return nil
 }
 
+// SignalProcess handles `docker exec` processes being stopped. Windows
+// processes can only be terminated, so the signal is ignored.
+func (clnt *client) SignalProcess(containerID string, processFriendlyName string, sig int) error {
+	clnt.lock(containerID)
+	defer clnt.unlock(containerID)
+	cont, err := clnt.getContainer(containerID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range cont.processes {
+		if p.friendlyName == processFriendlyName {
+			logrus.Debugf("lcd: SignalProcess() containerID=%s sig=%d pid=%d", containerID, sig, p.systemPid)
+			return hcsshim.TerminateProcessInComputeSystem(containerID, p.systemPid)
+		}
+	}
+
+	return fmt.Errorf("SignalProcess could not find process %s in %s", processFriendlyName, containerID)
+}
+
 // Resize handles a CLI event to resize an interactive docker run or docker exec
 // window.
 func (clnt *client) Resize(containerID, processFriendlyName string, width, height int) error {
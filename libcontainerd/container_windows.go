@@ -4,6 +4,7 @@ import (
 	"io"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Microsoft/hcsshim"
 	"github.com/Sirupsen/logrus"
@@ -90,6 +91,7 @@ func (ctr *container) start() error {
 	// Save the PID
 	logrus.Debugf("Process started - PID %d", pid)
 	ctr.systemPid = uint32(pid)
+	ctr.startedAt = time.Now()
 
 	// Spin up a go routine waiting for exit to handle cleanup
 	go ctr.waitExit(pid, InitFriendlyName, true)
@@ -168,7 +170,7 @@ func (ctr *container) waitExit(pid uint32, processFriendlyName string, isFirstPr
 		}
 
 		if si.State == StateExit && ctr.restartManager != nil {
-			restart, wait, err := ctr.restartManager.ShouldRestart(uint32(exitCode), false)
+			restart, wait, err := ctr.restartManager.ShouldRestart(uint32(exitCode), false, time.Since(ctr.startedAt))
 			if err != nil {
 				logrus.Error(err)
 			} else if restart {
@@ -411,6 +411,10 @@ func (l *mockLayer) ChainID() layer.ChainID {
 	return layer.CreateChainID(l.diffIDs)
 }
 
+func (l *mockLayer) TarStreamFrom(layer.ChainID) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (l *mockLayer) DiffID() layer.DiffID {
 	return l.diffIDs[len(l.diffIDs)-1]
 }
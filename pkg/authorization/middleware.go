@@ -2,6 +2,7 @@ package authorization
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/Sirupsen/logrus"
 	"golang.org/x/net/context"
@@ -10,20 +11,39 @@ import (
 // Middleware uses a list of plugins to
 // handle authorization in the API requests.
 type Middleware struct {
+	mu      sync.Mutex
 	plugins []Plugin
 }
 
 // NewMiddleware creates a new Middleware
 // with a slice of plugins.
-func NewMiddleware(p []Plugin) Middleware {
-	return Middleware{
+func NewMiddleware(p []Plugin) *Middleware {
+	return &Middleware{
 		plugins: p,
 	}
 }
 
+// SetPlugins replaces the plugins used to authorize the requests. An empty
+// slice disables authorization.
+func (m *Middleware) SetPlugins(p []Plugin) {
+	m.mu.Lock()
+	m.plugins = p
+	m.mu.Unlock()
+}
+
+func (m *Middleware) getPlugins() []Plugin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.plugins
+}
+
 // WrapHandler returns a new handler function wrapping the previous one in the request chain.
-func (m Middleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+func (m *Middleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		plugins := m.getPlugins()
+		if len(plugins) == 0 {
+			return handler(ctx, w, r, vars)
+		}
 
 		user := ""
 		userAuthNMethod := ""
@@ -37,7 +57,7 @@ func (m Middleware) WrapHandler(handler func(ctx context.Context, w http.Respons
 			userAuthNMethod = "TLS"
 		}
 
-		authCtx := NewCtx(m.plugins, user, userAuthNMethod, r.Method, r.RequestURI)
+		authCtx := NewCtx(plugins, user, userAuthNMethod, r.Method, r.RequestURI)
 
 		if err := authCtx.AuthZRequest(w, r); err != nil {
 			logrus.Errorf("AuthZRequest for %s %s returned error: %s", r.Method, r.RequestURI, err)
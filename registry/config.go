@@ -21,12 +21,35 @@ type ServiceOptions struct {
 	// V2Only controls access to legacy registries.  If it is set to true via the
 	// command line flag the daemon will not attempt to contact v1 legacy registries
 	V2Only bool `json:"disable-legacy-registry,omitempty"`
+
+	// MirrorsByHost are the mirrors of each registry, by registry hostname,
+	// in order of preference. The mirrors of Docker Hub are tried after
+	// the ones of Mirrors. It's only set in the configuration file.
+	MirrorsByHost map[string][]MirrorEndpoint `json:"registry-mirrors-by-host,omitempty"`
+}
+
+// MirrorEndpoint is a mirror of a registry, with its own TLS settings.
+type MirrorEndpoint struct {
+	// URL is the URL of the mirror, such as https://mirror.example.com.
+	URL string `json:"url"`
+	// Insecure disables the verification of the certificate of the
+	// mirror.
+	Insecure bool `json:"insecure,omitempty"`
+	// CACert is the path of the CA certificates to verify the mirror
+	// with, instead of the ones in the certs.d directory of the mirror.
+	CACert string `json:"tlscacert,omitempty"`
+	// Cert and Key are the paths of the client certificate and key to
+	// authenticate to the mirror with.
+	Cert string `json:"tlscert,omitempty"`
+	Key  string `json:"tlskey,omitempty"`
 }
 
 // serviceConfig holds daemon configuration for the registry service.
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+	// MirrorsByHost are the mirrors of the registries, by index name.
+	MirrorsByHost map[string][]MirrorEndpoint
 }
 
 var (
@@ -97,7 +120,14 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// and Mirrors are only for the official registry anyways.
 			Mirrors: options.Mirrors,
 		},
-		V2Only: options.V2Only,
+		V2Only:        options.V2Only,
+		MirrorsByHost: make(map[string][]MirrorEndpoint),
+	}
+	for host, mirrors := range options.MirrorsByHost {
+		// Docker Hub can be given by any of its names
+		if name, err := ValidateIndexName(host); err == nil {
+			config.MirrorsByHost[name] = append(config.MirrorsByHost[name], mirrors...)
+		}
 	}
 	// Split --insecure-registry into CIDR and registry-specific settings.
 	for _, r := range options.InsecureRegistries {
@@ -195,6 +225,28 @@ func ValidateMirror(val string) (string, error) {
 	return fmt.Sprintf("%s://%s/", uri.Scheme, uri.Host), nil
 }
 
+// ValidateMirrorsByHost validates the mirrors of the registries, by registry
+// hostname.
+func ValidateMirrorsByHost(mirrorsByHost map[string][]MirrorEndpoint) error {
+	for host, mirrors := range mirrorsByHost {
+		if _, err := ValidateIndexName(host); err != nil {
+			return err
+		}
+		if host == "" || strings.Contains(host, "/") {
+			return fmt.Errorf("Invalid registry %q, expected a hostname with an optional port", host)
+		}
+		for _, mirror := range mirrors {
+			if _, err := ValidateMirror(mirror.URL); err != nil {
+				return fmt.Errorf("Invalid mirror of %s: %v", host, err)
+			}
+			if (mirror.Cert == "") != (mirror.Key == "") {
+				return fmt.Errorf("Invalid mirror %s of %s: tlscert and tlskey must be set together", mirror.URL, host)
+			}
+		}
+	}
+	return nil
+}
+
 // ValidateIndexName validates an index name.
 func ValidateIndexName(val string) (string, error) {
 	if val == reference.LegacyDefaultHostname {
@@ -47,3 +47,27 @@ func TestValidateMirror(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateMirrorsByHost(t *testing.T) {
+	valid := map[string][]MirrorEndpoint{
+		"index.docker.io": {{URL: "https://hub-mirror.com"}},
+		"registry.com":    {{URL: "https://mirror-1.com"}, {URL: "http://mirror-2.com:5000", Insecure: true}},
+		"localhost:5000":  {{URL: "https://mirror-1.com", Cert: "client.cert", Key: "client.key"}},
+	}
+	if err := ValidateMirrorsByHost(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []map[string][]MirrorEndpoint{
+		{"registry.com/foo": {{URL: "https://mirror-1.com"}}},
+		{"-registry.com": {{URL: "https://mirror-1.com"}}},
+		{"registry.com": {{URL: "https://mirror-1.com/v1/"}}},
+		{"registry.com": {{URL: "mirror-1.com"}}},
+		{"registry.com": {{URL: "https://mirror-1.com", Cert: "client.cert"}}},
+	}
+	for _, mirrors := range invalid {
+		if err := ValidateMirrorsByHost(mirrors); err == nil {
+			t.Errorf("expected an error validating %v", mirrors)
+		}
+	}
+}
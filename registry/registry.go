@@ -42,6 +42,40 @@ func newTLSConfig(hostname string, isSecure bool) (*tls.Config, error) {
 	return &tlsConfig, nil
 }
 
+// newMirrorTLSConfig constructs the TLS configuration of a mirror on the
+// given host. The CA certificates and the client certificate of the mirror
+// are added to the ones of its certs.d directory.
+func newMirrorTLSConfig(hostname string, mirror MirrorEndpoint) (*tls.Config, error) {
+	tlsConfig, err := newTLSConfig(hostname, !mirror.Insecure)
+	if err != nil {
+		return nil, err
+	}
+	if mirror.Insecure {
+		return tlsConfig, nil
+	}
+
+	if mirror.CACert != "" {
+		data, err := ioutil.ReadFile(mirror.CACert)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig.RootCAs == nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("No CA certificate found in %s for mirror %s", mirror.CACert, mirror.URL)
+		}
+	}
+	if mirror.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(mirror.Cert, mirror.Key)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+	return tlsConfig, nil
+}
+
 func hasFile(files []os.FileInfo, name string) bool {
 	for _, f := range files {
 		if f.Name() == name {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -684,6 +685,82 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestMirrorsByHostEndpointLookup(t *testing.T) {
+	s := Service{config: newServiceConfig(ServiceOptions{
+		Mirrors: []string{"https://my.mirror"},
+		MirrorsByHost: map[string][]MirrorEndpoint{
+			"index.docker.io": {{URL: "https://hub.mirror"}},
+			"registry.com":    {{URL: "https://mirror-1.com"}, {URL: "http://mirror-2.com", Insecure: true}},
+		},
+	})}
+
+	for _, c := range []struct {
+		hostname string
+		expected []string
+	}{
+		{IndexName, []string{"my.mirror", "hub.mirror", DefaultV2Registry.Host}},
+		{"registry.com", []string{"mirror-1.com", "mirror-2.com", "registry.com"}},
+		{"other.com", []string{"other.com"}},
+	} {
+		pullAPIEndpoints, err := s.LookupPullEndpoints(c.hostname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hosts []string
+		for _, endpoint := range pullAPIEndpoints {
+			if endpoint.Version != APIVersion2 {
+				continue
+			}
+			if endpoint.Mirror != (endpoint.URL.Host != c.hostname && endpoint.URL.Host != DefaultV2Registry.Host) {
+				t.Fatalf("unexpected mirror flag for %s", endpoint.URL)
+			}
+			hosts = append(hosts, endpoint.URL.Host)
+		}
+		if !reflect.DeepEqual(hosts, c.expected) {
+			t.Fatalf("expected the endpoints %v for %s, got %v", c.expected, c.hostname, hosts)
+		}
+
+		pushAPIEndpoints, err := s.LookupPushEndpoints(c.hostname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, endpoint := range pushAPIEndpoints {
+			if endpoint.Mirror {
+				t.Fatalf("push endpoints should not contain the mirror %s", endpoint.URL)
+			}
+		}
+	}
+}
+
+func TestServiceReload(t *testing.T) {
+	s := NewService(ServiceOptions{Mirrors: []string{"https://my.mirror"}})
+
+	if err := s.Reload(ServiceOptions{Mirrors: []string{"not a mirror"}}); err == nil {
+		t.Fatal("expected an error reloading an invalid mirror")
+	}
+	if mirrors := s.ServiceConfig().Mirrors; len(mirrors) != 1 || mirrors[0] != "https://my.mirror" {
+		t.Fatalf("expected the mirrors to be untouched after a failed reload, got %v", mirrors)
+	}
+
+	err := s.Reload(ServiceOptions{
+		Mirrors:            []string{"https://other.mirror"},
+		InsecureRegistries: []string{"insecure.registry:5000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mirrors := s.ServiceConfig().Mirrors; len(mirrors) != 1 || mirrors[0] != "https://other.mirror" {
+		t.Fatalf("expected the reloaded mirrors, got %v", mirrors)
+	}
+	index, err := s.ResolveIndex("insecure.registry:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Secure {
+		t.Fatal("expected the reloaded insecure registry not to be secure")
+	}
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNamed(REPO)
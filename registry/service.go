@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/reference"
@@ -16,6 +17,7 @@ import (
 // Service is a registry service. It tracks configuration data such as a list
 // of mirrors.
 type Service struct {
+	mu     sync.Mutex
 	config *serviceConfig
 }
 
@@ -29,7 +31,40 @@ func NewService(options ServiceOptions) *Service {
 
 // ServiceConfig returns the public registry service configuration.
 func (s *Service) ServiceConfig() *registrytypes.ServiceConfig {
-	return &s.config.ServiceConfig
+	return &s.getConfig().ServiceConfig
+}
+
+// getConfig returns the current configuration of the service. It is never
+// modified, Reload replaces it instead.
+func (s *Service) getConfig() *serviceConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
+
+// Reload replaces the mirrors and the insecure registries of the service.
+// The options are validated first, so that the configuration is left
+// untouched if they are invalid.
+func (s *Service) Reload(options ServiceOptions) error {
+	for _, mirror := range options.Mirrors {
+		if _, err := ValidateMirror(mirror); err != nil {
+			return err
+		}
+	}
+	if err := ValidateMirrorsByHost(options.MirrorsByHost); err != nil {
+		return err
+	}
+	for _, r := range options.InsecureRegistries {
+		if _, err := ValidateIndexName(r); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	options.V2Only = s.config.V2Only
+	s.config = newServiceConfig(options)
+	return nil
 }
 
 // Auth contacts the public registry with the provided credentials,
@@ -97,7 +132,7 @@ func (s *Service) Search(term string, authConfig *types.AuthConfig, userAgent st
 
 	indexName, remoteName := splitReposSearchTerm(term)
 
-	index, err := newIndexInfo(s.config, indexName)
+	index, err := newIndexInfo(s.getConfig(), indexName)
 	if err != nil {
 		return nil, err
 	}
@@ -128,12 +163,12 @@ func (s *Service) Search(term string, authConfig *types.AuthConfig, userAgent st
 // ResolveRepository splits a repository name into its components
 // and configuration of the associated registry.
 func (s *Service) ResolveRepository(name reference.Named) (*RepositoryInfo, error) {
-	return newRepositoryInfo(s.config, name)
+	return newRepositoryInfo(s.getConfig(), name)
 }
 
 // ResolveIndex takes indexName and returns index info
 func (s *Service) ResolveIndex(name string) (*registrytypes.IndexInfo, error) {
-	return newIndexInfo(s.config, name)
+	return newIndexInfo(s.getConfig(), name)
 }
 
 // APIEndpoint represents a remote API endpoint
@@ -153,7 +188,7 @@ func (e APIEndpoint) ToV1Endpoint(userAgent string, metaHeaders http.Header) (*V
 
 // TLSConfig constructs a client TLS configuration based on server defaults
 func (s *Service) TLSConfig(hostname string) (*tls.Config, error) {
-	return newTLSConfig(hostname, isSecureIndex(s.config, hostname))
+	return newTLSConfig(hostname, isSecureIndex(s.getConfig(), hostname))
 }
 
 func (s *Service) tlsConfigForMirror(mirrorURL *url.URL) (*tls.Config, error) {
@@ -188,7 +223,7 @@ func (s *Service) lookupEndpoints(hostname string) (endpoints []APIEndpoint, err
 		return nil, err
 	}
 
-	if s.config.V2Only {
+	if s.getConfig().V2Only {
 		return endpoints, nil
 	}
 
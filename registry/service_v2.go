@@ -12,7 +12,7 @@ func (s *Service) lookupV2Endpoints(hostname string) (endpoints []APIEndpoint, e
 	tlsConfig := &cfg
 	if hostname == DefaultNamespace || hostname == DefaultV1Registry.Host {
 		// v2 mirrors
-		for _, mirror := range s.config.Mirrors {
+		for _, mirror := range s.getConfig().Mirrors {
 			if !strings.HasPrefix(mirror, "http://") && !strings.HasPrefix(mirror, "https://") {
 				mirror = "https://" + mirror
 			}
@@ -33,6 +33,12 @@ func (s *Service) lookupV2Endpoints(hostname string) (endpoints []APIEndpoint, e
 				TLSConfig:    mirrorTLSConfig,
 			})
 		}
+		// v2 mirrors of Docker Hub given by host, after the ones above
+		mirrors, err := s.mirrorEndpoints(IndexName)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, mirrors...)
 		// v2 registry
 		endpoints = append(endpoints, APIEndpoint{
 			URL:          DefaultV2Registry,
@@ -45,22 +51,26 @@ func (s *Service) lookupV2Endpoints(hostname string) (endpoints []APIEndpoint, e
 		return endpoints, nil
 	}
 
+	// v2 mirrors
+	endpoints, err = s.mirrorEndpoints(hostname)
+	if err != nil {
+		return nil, err
+	}
+
 	tlsConfig, err = s.TLSConfig(hostname)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoints = []APIEndpoint{
-		{
-			URL: &url.URL{
-				Scheme: "https",
-				Host:   hostname,
-			},
-			Version:      APIVersion2,
-			TrimHostname: true,
-			TLSConfig:    tlsConfig,
+	endpoints = append(endpoints, APIEndpoint{
+		URL: &url.URL{
+			Scheme: "https",
+			Host:   hostname,
 		},
-	}
+		Version:      APIVersion2,
+		TrimHostname: true,
+		TLSConfig:    tlsConfig,
+	})
 
 	if tlsConfig.InsecureSkipVerify {
 		endpoints = append(endpoints, APIEndpoint{
@@ -77,3 +87,26 @@ func (s *Service) lookupV2Endpoints(hostname string) (endpoints []APIEndpoint, e
 
 	return endpoints, nil
 }
+
+// mirrorEndpoints returns the endpoints of the mirrors configured for the
+// registry, in order of preference.
+func (s *Service) mirrorEndpoints(hostname string) (endpoints []APIEndpoint, err error) {
+	for _, mirror := range s.getConfig().MirrorsByHost[hostname] {
+		mirrorURL, err := url.Parse(mirror.URL)
+		if err != nil {
+			return nil, err
+		}
+		mirrorTLSConfig, err := newMirrorTLSConfig(mirrorURL.Host, mirror)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, APIEndpoint{
+			URL:          mirrorURL,
+			Version:      APIVersion2,
+			Mirror:       true,
+			TrimHostname: true,
+			TLSConfig:    mirrorTLSConfig,
+		})
+	}
+	return endpoints, nil
+}
@@ -9,14 +9,15 @@ import (
 )
 
 const (
-	backoffMultiplier = 2
-	defaultTimeout    = 100 * time.Millisecond
+	backoffMultiplier  = 2
+	defaultTimeout     = 100 * time.Millisecond
+	defaultResetPeriod = 10 * time.Second
 )
 
 // RestartManager defines object that controls container restarting rules.
 type RestartManager interface {
 	Cancel() error
-	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool) (bool, chan error, error)
+	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error)
 }
 
 type restartManager struct {
@@ -41,7 +42,7 @@ func (rm *restartManager) SetPolicy(policy container.RestartPolicy) {
 	rm.Unlock()
 }
 
-func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool) (bool, chan error, error) {
+func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error) {
 	rm.Lock()
 	unlockOnExit := true
 	defer func() {
@@ -58,6 +59,16 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		return false, nil, fmt.Errorf("invalid call on active restartmanager")
 	}
 
+	// a container that ran for long enough before exiting is considered to
+	// have been stable, so start the backoff over again
+	resetPeriod := rm.policy.ResetPeriod
+	if resetPeriod == 0 {
+		resetPeriod = defaultResetPeriod
+	}
+	if executionDuration >= resetPeriod {
+		rm.timeout = 0
+	}
+
 	if rm.timeout == 0 {
 		rm.timeout = defaultTimeout
 	} else {
@@ -70,7 +81,7 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		restart = true
 	case rm.policy.IsUnlessStopped() && !hasBeenManuallyStopped:
 		restart = true
-	case rm.policy.IsOnFailure():
+	case rm.policy.IsOnFailure(), rm.policy.IsOnUnhealthy():
 		// the default value of 0 for MaximumRetryCount means that we will not enforce a maximum count
 		if max := rm.policy.MaximumRetryCount; max == 0 || rm.restartCount < max {
 			restart = exitCode != 0
@@ -1,3 +1,68 @@
 package restartmanager
 
-// FIXME
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types/container"
+)
+
+func TestRestartManagerTimeout(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always"}, 0).(*restartManager)
+	should, _, err := rm.ShouldRestart(0, false, 1*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("container should be restarted")
+	}
+	if rm.timeout != defaultTimeout {
+		t.Fatalf("restart manager should have a timeout of %v, got %v", defaultTimeout, rm.timeout)
+	}
+}
+
+func TestRestartManagerTimeoutReset(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always", ResetPeriod: time.Minute}, 0).(*restartManager)
+	rm.timeout = 5 * time.Second
+	_, _, err := rm.ShouldRestart(0, false, 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rm.timeout != defaultTimeout {
+		t.Fatalf("restart manager should have reset its timeout to %v, got %v", defaultTimeout, rm.timeout)
+	}
+}
+
+func TestRestartManagerBackoffBeforeReset(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always", ResetPeriod: time.Minute}, 0).(*restartManager)
+	rm.timeout = 5 * time.Second
+	_, _, err := rm.ShouldRestart(0, false, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 10 * time.Second; rm.timeout != expected {
+		t.Fatalf("restart manager should have a timeout of %v, got %v", expected, rm.timeout)
+	}
+}
+
+func TestRestartManagerOnUnhealthy(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "on-unhealthy", UnhealthyThreshold: 3}, 0).(*restartManager)
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should {
+		t.Fatal("container should not be restarted after a clean exit")
+	}
+
+	should, _, err = rm.ShouldRestart(137, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("container should be restarted after being killed")
+	}
+	if rm.restartCount != 1 {
+		t.Fatalf("restart count should be 1, got %d", rm.restartCount)
+	}
+}
@@ -165,12 +165,13 @@ func TestPidModeTest(t *testing.T) {
 
 func TestRestartPolicy(t *testing.T) {
 	restartPolicies := map[container.RestartPolicy][]bool{
-		// none, always, failure
-		container.RestartPolicy{}:                {false, false, false},
-		container.RestartPolicy{"something", 0}:  {false, false, false},
-		container.RestartPolicy{"no", 0}:         {true, false, false},
-		container.RestartPolicy{"always", 0}:     {false, true, false},
-		container.RestartPolicy{"on-failure", 0}: {false, false, true},
+		// none, always, failure, unhealthy
+		container.RestartPolicy{}:                     {false, false, false, false},
+		container.RestartPolicy{Name: "something"}:    {false, false, false, false},
+		container.RestartPolicy{Name: "no"}:           {true, false, false, false},
+		container.RestartPolicy{Name: "always"}:       {false, true, false, false},
+		container.RestartPolicy{Name: "on-failure"}:   {false, false, true, false},
+		container.RestartPolicy{Name: "on-unhealthy"}: {false, false, false, true},
 	}
 	for restartPolicy, state := range restartPolicies {
 		if restartPolicy.IsNone() != state[0] {
@@ -182,6 +183,9 @@ func TestRestartPolicy(t *testing.T) {
 		if restartPolicy.IsOnFailure() != state[2] {
 			t.Fatalf("RestartPolicy.IsOnFailure for %v should have been %v but was %v", restartPolicy, state[2], restartPolicy.IsOnFailure())
 		}
+		if restartPolicy.IsOnUnhealthy() != state[3] {
+			t.Fatalf("RestartPolicy.IsOnUnhealthy for %v should have been %v but was %v", restartPolicy, state[3], restartPolicy.IsOnUnhealthy())
+		}
 	}
 }
 func TestDecodeHostConfig(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/runconfig"
@@ -384,6 +385,45 @@ func TestParseWithMemorySwap(t *testing.T) {
 	}
 }
 
+func TestParseHealth(t *testing.T) {
+	checkOk := func(args ...string) *container.HealthConfig {
+		config, _, _, _, err := parseRun(args)
+		if err != nil {
+			t.Fatalf("%#v: %v", args, err)
+		}
+		return config.Healthcheck
+	}
+	checkError := func(expected string, args ...string) {
+		config, _, _, _, err := parseRun(args)
+		if err == nil {
+			t.Fatalf("Expected error, but got %#v", config)
+		}
+		if err.Error() != expected {
+			t.Fatalf("Expected %#v, got %#v", expected, err)
+		}
+	}
+	health := checkOk("--no-healthcheck", "img", "cmd")
+	if health == nil || len(health.Test) != 1 || health.Test[0] != "NONE" {
+		t.Fatalf("--no-healthcheck failed: %#v", health)
+	}
+
+	health = checkOk("--health-cmd=/check.sh -q", "img", "cmd")
+	if len(health.Test) != 2 || health.Test[0] != "CMD-SHELL" || health.Test[1] != "/check.sh -q" {
+		t.Fatalf("--health-cmd: got %#v", health.Test)
+	}
+	if health.Timeout != 0 {
+		t.Fatalf("--health-cmd: timeout = %v", health.Timeout)
+	}
+
+	checkError("--no-healthcheck conflicts with --health-* options",
+		"--no-healthcheck", "--health-cmd=/check.sh -q", "img", "cmd")
+
+	health = checkOk("--health-timeout=2s", "--health-retries=3", "--health-interval=4.5s", "img", "cmd")
+	if health.Timeout != 2*time.Second || health.Retries != 3 || health.Interval != 4500*time.Millisecond {
+		t.Fatalf("--health-*: got %#v", health)
+	}
+}
+
 func TestParseHostname(t *testing.T) {
 	validHostnames := map[string]string{
 		"hostname":    "hostname",
@@ -569,6 +609,8 @@ func TestParseRestartPolicy(t *testing.T) {
 		"always:2:3":         "maximum restart count not valid with restart policy of \"always\"",
 		"on-failure:invalid": `strconv.ParseInt: parsing "invalid": invalid syntax`,
 		"on-failure:2:5":     "restart count format is not valid, usage: 'on-failure:N' or 'on-failure'",
+		"on-unhealthy:0":     "unhealthy threshold must be at least 1",
+		"on-unhealthy:2:5":   "unhealthy threshold format is not valid, usage: 'on-unhealthy:N' or 'on-unhealthy'",
 	}
 	valids := map[string]container.RestartPolicy{
 		"": {},
@@ -580,6 +622,13 @@ func TestParseRestartPolicy(t *testing.T) {
 			Name:              "on-failure",
 			MaximumRetryCount: 1,
 		},
+		"on-unhealthy": {
+			Name: "on-unhealthy",
+		},
+		"on-unhealthy:4": {
+			Name:               "on-unhealthy",
+			UnhealthyThreshold: 4,
+		},
 	}
 	for restart, expectedError := range invalids {
 		if _, _, _, _, err := parseRun([]string{fmt.Sprintf("--restart=%s", restart), "img", "cmd"}); err == nil || err.Error() != expectedError {
@@ -597,6 +646,20 @@ func TestParseRestartPolicy(t *testing.T) {
 	}
 }
 
+func TestParseRestartResetPeriod(t *testing.T) {
+	_, hostconfig, _, _, err := parseRun([]string{"--restart=always", "--restart-reset-period=1m", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostconfig.RestartPolicy.ResetPeriod != time.Minute {
+		t.Fatalf("Expected a reset period of 1m, got %v", hostconfig.RestartPolicy.ResetPeriod)
+	}
+
+	if _, _, _, _, err := parseRun([]string{"--restart-reset-period=-1s", "img", "cmd"}); err == nil {
+		t.Fatal("Expected an error for a negative reset period")
+	}
+}
+
 func TestParseLoggingOpts(t *testing.T) {
 	// logging opts ko
 	if _, _, _, _, err := parseRun([]string{"--log-driver=none", "--log-opt=anything", "img", "cmd"}); err == nil || err.Error() != "invalid logging opts for driver none" {